@@ -0,0 +1,130 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package annotate draws text, legends and overlays on rendered frames.
+//
+// It embeds its own bitmap font so images are readable without a browser.
+package annotate
+
+import (
+	"image"
+	"image/color"
+	"unicode"
+)
+
+// Size of a glyph of the embedded font, in pixels at scale 1.
+const (
+	GlyphWidth  = 5
+	GlyphHeight = 7
+)
+
+// TextWidth returns the width in pixels of s drawn with DrawText at scale.
+func TextWidth(s string, scale int) int {
+	n := 0
+	for range s {
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	return (n*(GlyphWidth+1) - 1) * scale
+}
+
+// TextHeight returns the height in pixels of a line of text at scale.
+func TextHeight(scale int) int {
+	return GlyphHeight * scale
+}
+
+// DrawText draws s with its top left corner at p with the embedded 5x7
+// bitmap font, each font pixel being scale x scale pixels.
+//
+// Lower case letters are drawn as upper case. Unsupported characters are
+// drawn as '?'.
+func DrawText(dst *image.NRGBA, p image.Point, s string, c color.NRGBA, scale int) {
+	if scale < 1 {
+		scale = 1
+	}
+	x := p.X
+	for _, r := range s {
+		g, ok := glyphs[unicode.ToUpper(r)]
+		if !ok {
+			g = glyphs['?']
+		}
+		for gy, row := range g {
+			for gx, b := range row {
+				if b == '#' {
+					fillRect(dst, image.Rect(x+gx*scale, p.Y+gy*scale, x+(gx+1)*scale, p.Y+(gy+1)*scale), c)
+				}
+			}
+		}
+		x += (GlyphWidth + 1) * scale
+	}
+}
+
+// Private details.
+
+// fillRect fills r with c, clipped to dst.
+func fillRect(dst *image.NRGBA, r image.Rectangle, c color.NRGBA) {
+	r = r.Intersect(dst.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			dst.SetNRGBA(x, y, c)
+		}
+	}
+}
+
+// glyphs is a 5x7 font, each string being a row.
+var glyphs = map[rune][GlyphHeight]string{
+	' ': {".....", ".....", ".....", ".....", ".....", ".....", "....."},
+	'#': {".#.#.", ".#.#.", "#####", ".#.#.", "#####", ".#.#.", ".#.#."},
+	'%': {"##...", "##..#", "...#.", "..#..", ".#...", "#..##", "...##"},
+	'(': {"...#.", "..#..", ".#...", ".#...", ".#...", "..#..", "...#."},
+	')': {".#...", "..#..", "...#.", "...#.", "...#.", "..#..", ".#..."},
+	'+': {".....", "..#..", "..#..", "#####", "..#..", "..#..", "....."},
+	',': {".....", ".....", ".....", ".....", ".##..", "..#..", ".#..."},
+	'-': {".....", ".....", ".....", "#####", ".....", ".....", "....."},
+	'.': {".....", ".....", ".....", ".....", ".....", ".##..", ".##.."},
+	'/': {".....", "....#", "...#.", "..#..", ".#...", "#....", "....."},
+	'0': {".###.", "#...#", "#..##", "#.#.#", "##..#", "#...#", ".###."},
+	'1': {"..#..", ".##..", "..#..", "..#..", "..#..", "..#..", ".###."},
+	'2': {".###.", "#...#", "....#", "...#.", "..#..", ".#...", "#####"},
+	'3': {"#####", "...#.", "..#..", "...#.", "....#", "#...#", ".###."},
+	'4': {"...#.", "..##.", ".#.#.", "#..#.", "#####", "...#.", "...#."},
+	'5': {"#####", "#....", "####.", "....#", "....#", "#...#", ".###."},
+	'6': {"..##.", ".#...", "#....", "####.", "#...#", "#...#", ".###."},
+	'7': {"#####", "....#", "...#.", "..#..", ".#...", ".#...", ".#..."},
+	'8': {".###.", "#...#", "#...#", ".###.", "#...#", "#...#", ".###."},
+	'9': {".###.", "#...#", "#...#", ".####", "....#", "...#.", ".##.."},
+	':': {".....", ".##..", ".##..", ".....", ".##..", ".##..", "....."},
+	'=': {".....", ".....", "#####", ".....", "#####", ".....", "....."},
+	'?': {".###.", "#...#", "....#", "...#.", "..#..", ".....", "..#.."},
+	'A': {".###.", "#...#", "#...#", "#####", "#...#", "#...#", "#...#"},
+	'B': {"####.", "#...#", "#...#", "####.", "#...#", "#...#", "####."},
+	'C': {".###.", "#...#", "#....", "#....", "#....", "#...#", ".###."},
+	'D': {"###..", "#..#.", "#...#", "#...#", "#...#", "#..#.", "###.."},
+	'E': {"#####", "#....", "#....", "####.", "#....", "#....", "#####"},
+	'F': {"#####", "#....", "#....", "####.", "#....", "#....", "#...."},
+	'G': {".###.", "#...#", "#....", "#.###", "#...#", "#...#", ".####"},
+	'H': {"#...#", "#...#", "#...#", "#####", "#...#", "#...#", "#...#"},
+	'I': {".###.", "..#..", "..#..", "..#..", "..#..", "..#..", ".###."},
+	'J': {"..###", "...#.", "...#.", "...#.", "...#.", "#..#.", ".##.."},
+	'K': {"#...#", "#..#.", "#.#..", "##...", "#.#..", "#..#.", "#...#"},
+	'L': {"#....", "#....", "#....", "#....", "#....", "#....", "#####"},
+	'M': {"#...#", "##.##", "#.#.#", "#.#.#", "#...#", "#...#", "#...#"},
+	'N': {"#...#", "#...#", "##..#", "#.#.#", "#..##", "#...#", "#...#"},
+	'O': {".###.", "#...#", "#...#", "#...#", "#...#", "#...#", ".###."},
+	'P': {"####.", "#...#", "#...#", "####.", "#....", "#....", "#...."},
+	'Q': {".###.", "#...#", "#...#", "#...#", "#.#.#", "#..#.", ".##.#"},
+	'R': {"####.", "#...#", "#...#", "####.", "#.#..", "#..#.", "#...#"},
+	'S': {".####", "#....", "#....", ".###.", "....#", "....#", "####."},
+	'T': {"#####", "..#..", "..#..", "..#..", "..#..", "..#..", "..#.."},
+	'U': {"#...#", "#...#", "#...#", "#...#", "#...#", "#...#", ".###."},
+	'V': {"#...#", "#...#", "#...#", "#...#", "#...#", ".#.#.", "..#.."},
+	'W': {"#...#", "#...#", "#...#", "#.#.#", "#.#.#", "#.#.#", ".#.#."},
+	'X': {"#...#", "#...#", ".#.#.", "..#..", ".#.#.", "#...#", "#...#"},
+	'Y': {"#...#", "#...#", ".#.#.", "..#..", "..#..", "..#..", "..#.."},
+	'Z': {"#####", "....#", "...#.", "..#..", ".#...", "#....", "#####"},
+	'_': {".....", ".....", ".....", ".....", ".....", ".....", "#####"},
+	'°': {".##..", "#..#.", "#..#.", ".##..", ".....", ".....", "....."},
+}
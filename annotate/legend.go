@@ -0,0 +1,154 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package annotate
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"strconv"
+
+	"github.com/maruel/go-lepton/gray14"
+	"github.com/maruel/go-lepton/radiometry"
+	"periph.io/x/periph/conn/physic"
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// LegendOpts controls how Legend lays out the colorbar and the labels.
+type LegendOpts struct {
+	// Palette is the palette the frame was rendered with. Defaults to
+	// gray14.DefaultPalette.
+	Palette *gray14.Palette
+	// Linear converts counts to temperatures for the labels. When nil, the
+	// labels are in raw counts.
+	Linear *radiometry.Linear
+	// Kelvin labels temperatures in Kelvin instead of Celsius.
+	Kelvin bool
+	// Ticks is the number of labels along the colorbar. Minimum is 2.
+	Ticks int
+	// Scale is the font scale. Minimum is 1.
+	Scale int
+}
+
+// DefaultLegendOpts is the default options for Legend.
+var DefaultLegendOpts = LegendOpts{Ticks: 5, Scale: 1}
+
+// Legend returns a new image with the frame on the left, a colorbar with tick
+// labels on its right and a min/max/mean readout below.
+//
+// lo and hi are the intensities that were mapped to the first and last entries
+// of the palette when frame was rendered, e.g. gray14.Min() and gray14.Max()
+// for gray14.AGCLinear(). s may be nil, in which case there is no readout.
+func Legend(frame *image.NRGBA, lo, hi image14bit.Intensity14, s *gray14.Statistics, opts *LegendOpts) *image.NRGBA {
+	o := DefaultLegendOpts
+	if opts != nil {
+		o = *opts
+	}
+	if o.Palette == nil {
+		o.Palette = gray14.DefaultPalette
+	}
+	if o.Ticks < 2 {
+		o.Ticks = 2
+	}
+	if o.Scale < 1 {
+		o.Scale = 1
+	}
+	sc := o.Scale
+	margin := 2 * sc
+	th := TextHeight(sc)
+	fb := frame.Bounds()
+
+	labels := make([]string, o.Ticks)
+	labelsW := 0
+	for k := range labels {
+		v := float64(lo) + float64(int(hi)-int(lo))*float64(k)/float64(o.Ticks-1)
+		labels[k] = o.format(v)
+		if w := TextWidth(labels[k], sc); w > labelsW {
+			labelsW = w
+		}
+	}
+	var readout []string
+	if s != nil {
+		readout = []string{
+			"MIN " + o.format(float64(s.Min)),
+			"MAX " + o.format(float64(s.Max)),
+			"MEAN " + o.format(s.Mean),
+		}
+	}
+
+	barX := fb.Dx() + margin
+	barW := 8 * sc
+	labelX := barX + barW + 5*sc
+	w := labelX + labelsW + margin
+	// Put the readout on a single line when it fits, one item per line
+	// otherwise.
+	lines := readout
+	if len(readout) != 0 {
+		if l := readout[0] + " " + readout[1] + " " + readout[2]; TextWidth(l, sc)+2*margin <= w {
+			lines = []string{l}
+		}
+		for _, l := range lines {
+			if lw := TextWidth(l, sc) + 2*margin; lw > w {
+				w = lw
+			}
+		}
+	}
+	h := fb.Dy()
+	if m := o.Ticks * (th + sc); h < m {
+		h = m
+	}
+	readoutY := h + margin
+	if len(lines) != 0 {
+		h += len(lines)*(th+sc) + 2*margin - sc
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(dst, dst.Bounds(), &image.Uniform{color.NRGBA{0, 0, 0, 255}}, image.Point{}, draw.Src)
+	draw.Draw(dst, image.Rect(0, 0, fb.Dx(), fb.Dy()), frame, fb.Min, draw.Src)
+
+	// The colorbar is inset by half a line so the top and bottom labels are
+	// centered on their tick.
+	top := th / 2
+	bottom := readoutY - margin - th/2
+	if len(lines) == 0 {
+		bottom = h - th/2
+	}
+	span := bottom - top - 1
+	for y := top; y < bottom; y++ {
+		c := o.Palette.At(uint8(255 * (bottom - 1 - y) / span))
+		fillRect(dst, image.Rect(barX, y, barX+barW, y+1), c)
+	}
+	white := color.NRGBA{255, 255, 255, 255}
+	for k, l := range labels {
+		y := bottom - 1 - span*k/(o.Ticks-1)
+		fillRect(dst, image.Rect(barX+barW, y, barX+barW+2*sc, y+sc), white)
+		DrawText(dst, image.Pt(labelX, y-th/2), l, white, sc)
+	}
+	for n, l := range lines {
+		DrawText(dst, image.Pt(margin, readoutY+n*(th+sc)), l, white, sc)
+	}
+	return dst
+}
+
+// Private details.
+
+// format formats a count in the configured units.
+func (o *LegendOpts) format(v float64) string {
+	return formatValue(v, o.Linear, o.Kelvin)
+}
+
+// formatValue formats a count as a temperature with l, or as a count if l is
+// nil.
+func formatValue(v float64, l *radiometry.Linear, kelvin bool) string {
+	if l == nil {
+		return strconv.Itoa(int(v + 0.5))
+	}
+	t := l.Offset + physic.Temperature(v*float64(l.Gain))
+	if kelvin {
+		return fmt.Sprintf("%.1fK", radiometry.Kelvin(t))
+	}
+	return fmt.Sprintf("%.1f°C", radiometry.Celsius(t))
+}
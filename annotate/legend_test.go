@@ -0,0 +1,84 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package annotate
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/maruel/go-lepton/gray14"
+	"github.com/maruel/go-lepton/radiometry"
+	"periph.io/x/periph/conn/physic"
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+func TestLegend(t *testing.T) {
+	i := image14bit.NewGray14(image.Rect(0, 0, 80, 60))
+	for n := range i.Pix {
+		i.Pix[n] = uint16(8000 + n%80)
+	}
+	frame := gray14.DefaultPalette.Colorize(gray14.AGCLinear(i))
+	s := gray14.Stats(i, nil)
+	dst := Legend(frame, gray14.Min(i), gray14.Max(i), s, nil)
+	b := dst.Bounds()
+	if b.Dx() <= 80 || b.Dy() <= 60 {
+		t.Fatal(b)
+	}
+	// The frame is copied as is.
+	if c := dst.NRGBAAt(79, 59); c != frame.NRGBAAt(79, 59) {
+		t.Fatal(c)
+	}
+	// The colorbar goes from the last palette entry at the top to the first
+	// one at the bottom.
+	if c := dst.NRGBAAt(82, TextHeight(1)/2); c != gray14.DefaultPalette.At(255) {
+		t.Fatal(c)
+	}
+	if c := dst.NRGBAAt(82, 60-TextHeight(1)/2-1); c != gray14.DefaultPalette.At(0) {
+		t.Fatal(c)
+	}
+
+	l := radiometry.Linear{Offset: physic.ZeroCelsius, Gain: 10 * physic.MilliKelvin}
+	o := LegendOpts{Linear: &l, Ticks: 5, Scale: 2}
+	if s := o.format(2550); s != "25.5°C" {
+		t.Fatal(s)
+	}
+	o.Kelvin = true
+	if s := o.format(2550); s != "298.6K" {
+		t.Fatal(s)
+	}
+	// The frame is too short for 5 labels at scale 2.
+	if dst2 := Legend(frame, 8000, 8079, nil, &o); dst2.Bounds().Dy() != 80 {
+		t.Fatal(dst2.Bounds())
+	}
+}
+
+func TestDrawText(t *testing.T) {
+	if w := TextWidth("12", 1); w != 11 {
+		t.Fatal(w)
+	}
+	if w := TextWidth("°C", 2); w != 22 {
+		t.Fatal(w)
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, 11, 7))
+	white := color.NRGBA{255, 255, 255, 255}
+	DrawText(dst, image.Point{}, "-a", white, 1)
+	// '-' is the middle row.
+	for x := 0; x < 5; x++ {
+		if c := dst.NRGBAAt(x, 3); c != white {
+			t.Fatal(x, c)
+		}
+		if c := dst.NRGBAAt(x, 2); c.A != 0 {
+			t.Fatal(x, c)
+		}
+	}
+	// 'a' is drawn as 'A', whose apex is at the top center.
+	if c := dst.NRGBAAt(6+2, 0); c != white {
+		t.Fatal(c)
+	}
+	if c := dst.NRGBAAt(6, 0); c.A != 0 {
+		t.Fatal(c)
+	}
+}
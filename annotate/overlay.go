@@ -0,0 +1,202 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package annotate
+
+import (
+	"image"
+	"image/color"
+	"time"
+
+	"github.com/maruel/go-lepton/gray14"
+	"github.com/maruel/go-lepton/radiometry"
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// Colors used by Overlay. They match the ones used by the web UI.
+var (
+	ColdColor  = color.NRGBA{0, 0, 255, 255}
+	HotColor   = color.NRGBA{255, 0, 0, 255}
+	SpotColor  = color.NRGBA{0, 255, 0, 255}
+	ROIColor   = color.NRGBA{255, 255, 0, 255}
+	TextColor  = color.NRGBA{255, 255, 255, 255}
+	ShadeColor = color.NRGBA{0, 0, 0, 255}
+)
+
+// Overlay describes the annotations to draw on a rendered frame.
+type Overlay struct {
+	// Crosshair draws a crosshair at the center of the frame with the value
+	// under it.
+	Crosshair bool
+	// MinMax circles the coldest pixel in blue and the hottest in red.
+	MinMax bool
+	// Spots are pixels, in frame coordinates, whose value is printed.
+	Spots []image.Point
+	// Regions are outlined and labeled with their name.
+	Regions []gray14.Region
+	// Timestamp is printed at the bottom left if not zero.
+	Timestamp time.Time
+	// Camera is printed at the top left if not empty.
+	Camera string
+	// Linear converts counts to temperatures for the printed values. When nil,
+	// the values are in raw counts.
+	Linear *radiometry.Linear
+	// Kelvin prints temperatures in Kelvin instead of Celsius.
+	Kelvin bool
+	// Scale is the font scale. Minimum is 1.
+	Scale int
+}
+
+// Draw draws the annotations on dst, which is a rendering of frame at any
+// size. s are the statistics of frame and is only used for MinMax; it is
+// calculated if nil.
+func (o *Overlay) Draw(dst *image.NRGBA, frame *image14bit.Gray14, s *gray14.Statistics) {
+	sc := o.Scale
+	if sc < 1 {
+		sc = 1
+	}
+	m := newMapping(dst.Bounds(), frame.Bounds())
+	for _, r := range o.Regions {
+		m.outline(dst, r.ROI, ROIColor, sc)
+		if r.Name != "" {
+			p := m.corner(r.ROI.Bounds().Min)
+			drawLabel(dst, image.Pt(p.X+sc, p.Y+sc), r.Name, ROIColor, sc)
+		}
+	}
+	if o.MinMax {
+		if s == nil {
+			s = gray14.Stats(frame, nil)
+		}
+		if s.Count != 0 {
+			// The radius is 2 frame pixels, like in the web UI.
+			r := 2 * m.sx
+			if r < 3*sc {
+				r = 3 * sc
+			}
+			drawCircle(dst, m.center(s.MinPos), r, ColdColor, sc)
+			drawCircle(dst, m.center(s.MaxPos), r, HotColor, sc)
+		}
+	}
+	if o.Crosshair {
+		b := frame.Bounds()
+		p := image.Pt((b.Min.X+b.Max.X)/2, (b.Min.Y+b.Max.Y)/2)
+		c := m.center(p)
+		l := 4 * sc
+		fillRect(dst, image.Rect(c.X-l, c.Y, c.X+l+1, c.Y+sc), TextColor)
+		fillRect(dst, image.Rect(c.X, c.Y-l, c.X+sc, c.Y+l+1), TextColor)
+		drawLabel(dst, image.Pt(c.X+l+2*sc, c.Y+2*sc), o.value(frame, p), TextColor, sc)
+	}
+	for _, p := range o.Spots {
+		if !p.In(frame.Bounds()) {
+			continue
+		}
+		c := m.center(p)
+		fillRect(dst, image.Rect(c.X-sc, c.Y-sc, c.X+2*sc, c.Y+2*sc), SpotColor)
+		drawLabel(dst, image.Pt(c.X+3*sc, c.Y-TextHeight(sc)-sc), o.value(frame, p), SpotColor, sc)
+	}
+	b := dst.Bounds()
+	if o.Camera != "" {
+		drawLabel(dst, image.Pt(b.Min.X+sc, b.Min.Y+sc), o.Camera, TextColor, sc)
+	}
+	if !o.Timestamp.IsZero() {
+		t := o.Timestamp.Format("2006-01-02 15:04:05")
+		drawLabel(dst, image.Pt(b.Min.X+sc, b.Max.Y-TextHeight(sc)-2*sc), t, TextColor, sc)
+	}
+}
+
+// Private details.
+
+// value returns the formatted value of the pixel p of frame.
+func (o *Overlay) value(frame *image14bit.Gray14, p image.Point) string {
+	return formatValue(float64(frame.Intensity14At(p.X, p.Y)), o.Linear, o.Kelvin)
+}
+
+// mapping maps frame coordinates to rendered image coordinates.
+type mapping struct {
+	dst, src image.Rectangle
+	sx, sy   int // Approximate size of a frame pixel in the rendered image.
+}
+
+func newMapping(dst, src image.Rectangle) mapping {
+	m := mapping{dst: dst, src: src, sx: dst.Dx() / src.Dx(), sy: dst.Dy() / src.Dy()}
+	if m.sx < 1 {
+		m.sx = 1
+	}
+	if m.sy < 1 {
+		m.sy = 1
+	}
+	return m
+}
+
+// corner returns the top left corner of the frame pixel p.
+func (m *mapping) corner(p image.Point) image.Point {
+	return image.Pt(
+		m.dst.Min.X+(p.X-m.src.Min.X)*m.dst.Dx()/m.src.Dx(),
+		m.dst.Min.Y+(p.Y-m.src.Min.Y)*m.dst.Dy()/m.src.Dy())
+}
+
+// center returns the center of the frame pixel p.
+func (m *mapping) center(p image.Point) image.Point {
+	return image.Pt(
+		m.dst.Min.X+((2*(p.X-m.src.Min.X)+1)*m.dst.Dx())/(2*m.src.Dx()),
+		m.dst.Min.Y+((2*(p.Y-m.src.Min.Y)+1)*m.dst.Dy())/(2*m.src.Dy()))
+}
+
+// outline draws the edges between the pixels inside r and the ones outside,
+// so any kind of ROI is outlined at any size.
+//
+// Only the pixels inside the frame are visited, since r comes from the
+// client and can be arbitrarily large.
+func (m *mapping) outline(dst *image.NRGBA, r gray14.ROI, c color.NRGBA, width int) {
+	b := r.Bounds().Intersect(m.src)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			p := image.Pt(x, y)
+			if !r.Contains(p) {
+				continue
+			}
+			tl := m.corner(p)
+			br := m.corner(p.Add(image.Pt(1, 1)))
+			if !r.Contains(image.Pt(p.X, p.Y-1)) {
+				fillRect(dst, image.Rect(tl.X, tl.Y, br.X, tl.Y+width), c)
+			}
+			if !r.Contains(image.Pt(p.X, p.Y+1)) {
+				fillRect(dst, image.Rect(tl.X, br.Y-width, br.X, br.Y), c)
+			}
+			if !r.Contains(image.Pt(p.X-1, p.Y)) {
+				fillRect(dst, image.Rect(tl.X, tl.Y, tl.X+width, br.Y), c)
+			}
+			if !r.Contains(image.Pt(p.X+1, p.Y)) {
+				fillRect(dst, image.Rect(br.X-width, tl.Y, br.X, br.Y), c)
+			}
+		}
+	}
+}
+
+// drawCircle draws a circle outline of radius r centered on p.
+func drawCircle(dst *image.NRGBA, p image.Point, r int, c color.NRGBA, width int) {
+	// Midpoint circle algorithm, with each point drawn as a width x width
+	// square.
+	x, y, d := r, 0, 1-r
+	for x >= y {
+		for _, o := range [...]image.Point{{x, y}, {y, x}, {-y, x}, {-x, y}, {-x, -y}, {-y, -x}, {y, -x}, {x, -y}} {
+			q := p.Add(o)
+			fillRect(dst, image.Rect(q.X-width/2, q.Y-width/2, q.X-width/2+width, q.Y-width/2+width), c)
+		}
+		y++
+		if d < 0 {
+			d += 2*y + 1
+		} else {
+			x--
+			d += 2*(y-x) + 1
+		}
+	}
+}
+
+// drawLabel draws text with a dark shade so it is readable on any
+// background.
+func drawLabel(dst *image.NRGBA, p image.Point, s string, c color.NRGBA, scale int) {
+	DrawText(dst, p.Add(image.Pt(scale, scale)), s, ShadeColor, scale)
+	DrawText(dst, p, s, c, scale)
+}
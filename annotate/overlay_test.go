@@ -0,0 +1,88 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package annotate
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"github.com/maruel/go-lepton/gray14"
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+func TestOverlay(t *testing.T) {
+	i := image14bit.NewGray14(image.Rect(0, 0, 80, 60))
+	for n := range i.Pix {
+		i.Pix[n] = 8000
+	}
+	i.SetIntensity14(10, 10, 7000)
+	i.SetIntensity14(70, 50, 9000)
+	dst := image.NewNRGBA(image.Rect(0, 0, 800, 600))
+	o := Overlay{
+		MinMax:  true,
+		Regions: []gray14.Region{{ROI: gray14.Rect(image.Rect(20, 20, 30, 30))}},
+	}
+	o.Draw(dst, i, nil)
+	// The markers are circles of 2 frame pixels around the pixel centers.
+	if c := dst.NRGBAAt(105+20, 105); c != ColdColor {
+		t.Fatal(c)
+	}
+	if c := dst.NRGBAAt(705, 505-20); c != HotColor {
+		t.Fatal(c)
+	}
+	if c := dst.NRGBAAt(105, 105); c.A != 0 {
+		t.Fatal(c)
+	}
+	// The ROI is outlined on the inside of its border pixels.
+	if c := dst.NRGBAAt(250, 200); c != ROIColor {
+		t.Fatal(c)
+	}
+	if c := dst.NRGBAAt(299, 250); c != ROIColor {
+		t.Fatal(c)
+	}
+	if c := dst.NRGBAAt(250, 250); c.A != 0 {
+		t.Fatal(c)
+	}
+}
+
+func TestOverlayText(t *testing.T) {
+	i := image14bit.NewGray14(image.Rect(0, 0, 80, 60))
+	dst := image.NewNRGBA(image.Rect(0, 0, 80, 60))
+	o := Overlay{Camera: "A", Timestamp: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	o.Draw(dst, i, nil)
+	// The 'A' apex.
+	if c := dst.NRGBAAt(1+2, 1); c != TextColor {
+		t.Fatal(c)
+	}
+	// The shade is offset by one pixel.
+	if c := dst.NRGBAAt(1+2+1, 1+1); c != ShadeColor {
+		t.Fatal(c)
+	}
+	// The timestamp starts with '2', whose top row is ".###.".
+	if c := dst.NRGBAAt(1+1, 60-9); c != TextColor {
+		t.Fatal(c)
+	}
+	if c := (&Overlay{}).value(i, image.Pt(0, 0)); c != "0" {
+		t.Fatal(c)
+	}
+}
+
+func TestOverlayHugeROI(t *testing.T) {
+	i := image14bit.NewGray14(image.Rect(0, 0, 80, 60))
+	dst := image.NewNRGBA(image.Rect(0, 0, 800, 600))
+	// Only the part inside the frame is visited.
+	o := Overlay{Regions: []gray14.Region{{ROI: gray14.Rect(image.Rect(-1<<30, -1<<30, 40, 30))}}}
+	o.Draw(dst, i, nil)
+	if c := dst.NRGBAAt(399, 100); c != ROIColor {
+		t.Fatal(c)
+	}
+	if c := dst.NRGBAAt(100, 299); c != ROIColor {
+		t.Fatal(c)
+	}
+	if c := dst.NRGBAAt(100, 100); c.A != 0 {
+		t.Fatal(c)
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package background models the static part of a scene to segment what is
+// in front of it.
+//
+// Each pixel is modeled as a running Gaussian. A pixel too far from its
+// mean, in number of standard deviations, is foreground. Background pixels
+// are learned continuously so the model follows the ambient temperature
+// drift, while foreground pixels are learned much slower so someone standing
+// still is not absorbed into the background right away.
+package background
+
+import (
+	"image"
+	"math"
+	"sort"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// Opts are the parameters of Model.
+type Opts struct {
+	// LearningRate is the weight [0, 1] of a new frame on a background pixel.
+	LearningRate float64
+	// ForegroundRate is the weight [0, 1] of a new frame on a foreground
+	// pixel. It determines how long a still object takes to become part of
+	// the background.
+	ForegroundRate float64
+	// Threshold is the number of standard deviations from the mean at which
+	// a pixel is foreground.
+	Threshold float64
+	// MinStdDev is the minimum standard deviation in counts, to not flag the
+	// sensor noise on very stable pixels.
+	MinStdDev float64
+	// WarmUp is the number of frames used to learn the initial background.
+	// No foreground is reported during that time.
+	WarmUp int
+}
+
+// DefaultOpts are defaults suitable for a Lepton running at ~9Hz. A still
+// person is absorbed in about 5 minutes.
+var DefaultOpts = Opts{
+	LearningRate:   0.01,
+	ForegroundRate: 0.0002,
+	Threshold:      3,
+	MinStdDev:      4,
+	WarmUp:         18,
+}
+
+// Model is an adaptive per-pixel background model.
+//
+// It is not safe for concurrent use.
+type Model struct {
+	opts     Opts
+	bounds   image.Rectangle
+	frames   int
+	mean     []float32
+	variance []float32
+}
+
+// New returns an initialized Model. If opts is nil, DefaultOpts is used.
+func New(opts *Opts) *Model {
+	m := &Model{opts: DefaultOpts}
+	if opts != nil {
+		m.opts = *opts
+	}
+	return m
+}
+
+// Reset forgets the learned background.
+func (m *Model) Reset() {
+	m.frames = 0
+	m.mean = nil
+	m.variance = nil
+}
+
+// Frames returns the number of frames learned since the last reset.
+func (m *Model) Frames() int {
+	return m.frames
+}
+
+// Update learns i and returns its foreground mask, where the foreground
+// pixels are 255 and the background ones 0.
+//
+// A change in the overall level of the scene, like after a FFC, is
+// compensated for right away. The model is reset when the bounds of the
+// image change.
+func (m *Model) Update(i *image14bit.Gray14) *image.Gray {
+	b := i.Bounds()
+	mask := image.NewGray(b)
+	if m.mean == nil || b != m.bounds {
+		m.bounds = b
+		m.mean = make([]float32, b.Dx()*b.Dy())
+		m.variance = make([]float32, len(m.mean))
+		n := 0
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				m.mean[n] = float32(i.Intensity14At(x, y))
+				m.variance[n] = float32(m.opts.MinStdDev * m.opts.MinStdDev)
+				n++
+			}
+		}
+		m.frames = 1
+		return mask
+	}
+	m.frames++
+	warmingUp := m.frames <= m.opts.WarmUp
+
+	// Estimate the global drift with the median difference. It is not skewed
+	// by the foreground as long as it covers less than half of the scene.
+	drift := float32(0)
+	if !warmingUp {
+		diffs := make([]float32, 0, len(m.mean))
+		m.each(i, func(n int, d float32) {
+			diffs = append(diffs, d)
+		})
+		sort.Slice(diffs, func(i, j int) bool { return diffs[i] < diffs[j] })
+		drift = diffs[len(diffs)/2]
+	}
+
+	rate := float32(m.opts.LearningRate)
+	if warmingUp && 1/float32(m.frames) > rate {
+		// Average the first frames evenly.
+		rate = 1 / float32(m.frames)
+	}
+	fgRate := float32(m.opts.ForegroundRate)
+	m.each(i, func(n int, d float32) {
+		m.mean[n] += drift
+		d -= drift
+		r := rate
+		if !warmingUp && m.isForeground(n, d) {
+			r = fgRate
+			mask.Pix[mask.PixOffset(b.Min.X+n%b.Dx(), b.Min.Y+n/b.Dx())] = 255
+		}
+		m.mean[n] += r * d
+		m.variance[n] = (1-r)*m.variance[n] + r*d*d
+	})
+	return mask
+}
+
+// Background returns the current estimate of the background.
+func (m *Model) Background() *image14bit.Gray14 {
+	i := image14bit.NewGray14(m.bounds)
+	for n, v := range m.mean {
+		if v < 0 {
+			v = 0
+		} else if v > math.MaxUint16 {
+			v = math.MaxUint16
+		}
+		i.Pix[i.PixOffset(m.bounds.Min.X+n%m.bounds.Dx(), m.bounds.Min.Y+n/m.bounds.Dx())] = uint16(v + 0.5)
+	}
+	return i
+}
+
+// Private details.
+
+// each calls fn with the index and the difference from the mean of each
+// pixel of i.
+func (m *Model) each(i *image14bit.Gray14, fn func(n int, d float32)) {
+	n := 0
+	for y := m.bounds.Min.Y; y < m.bounds.Max.Y; y++ {
+		for x := m.bounds.Min.X; x < m.bounds.Max.X; x++ {
+			fn(n, float32(i.Intensity14At(x, y))-m.mean[n])
+			n++
+		}
+	}
+}
+
+// isForeground returns true if the difference d from the mean is too large
+// for the pixel n.
+func (m *Model) isForeground(n int, d float32) bool {
+	sd := math.Sqrt(float64(m.variance[n]))
+	if sd < m.opts.MinStdDev {
+		sd = m.opts.MinStdDev
+	}
+	return math.Abs(float64(d)) > m.opts.Threshold*sd
+}
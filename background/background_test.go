@@ -0,0 +1,82 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package background
+
+import (
+	"image"
+	"math/rand"
+	"testing"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+func TestModel(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	m := New(nil)
+	frame := func(level int, person bool) *image14bit.Gray14 {
+		i := image14bit.NewGray14(image.Rect(0, 0, 80, 60))
+		for y := 0; y < 60; y++ {
+			for x := 0; x < 80; x++ {
+				v := level + x + int(r.NormFloat64()*2)
+				if person && x >= 30 && x < 40 && y >= 20 && y < 50 {
+					v += 300
+				}
+				i.SetIntensity14(x, y, image14bit.Intensity14(v))
+			}
+		}
+		return i
+	}
+	for n := 0; n < DefaultOpts.WarmUp; n++ {
+		if c := count(m.Update(frame(8000, false))); c != 0 {
+			t.Fatal(n, c)
+		}
+	}
+	if c := count(m.Update(frame(8000, false))); c != 0 {
+		t.Fatal(c)
+	}
+	// The person stands still, while the room warms up slowly then a FFC
+	// shifts everything.
+	for n := 0; n < 100; n++ {
+		if c := count(m.Update(frame(8000+n/10, true))); c != 300 {
+			t.Fatal(n, c)
+		}
+	}
+	if c := count(m.Update(frame(8100, true))); c != 300 {
+		t.Fatal(c)
+	}
+	if v := m.Background().Intensity14At(0, 0); v < 8095 || v > 8105 {
+		t.Fatal(v)
+	}
+	if v := m.Background().Intensity14At(35, 30); v > 8200 {
+		t.Fatal(v)
+	}
+	m.Reset()
+	if m.Frames() != 0 {
+		t.Fatal(m.Frames())
+	}
+}
+
+func TestModelTLinear(t *testing.T) {
+	// TLinear counts use the whole 16 bits; 29315 is 20°C.
+	m := New(nil)
+	i := image14bit.NewGray14(image.Rect(0, 0, 4, 4))
+	for n := range i.Pix {
+		i.Pix[n] = 29315
+	}
+	m.Update(i)
+	if v := m.Background().Intensity14At(1, 1); v != 29315 {
+		t.Fatal(v)
+	}
+}
+
+func count(mask *image.Gray) int {
+	n := 0
+	for _, v := range mask.Pix {
+		if v != 0 {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,256 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package badpixel detects defective pixels on a camera and replaces them
+// with values interpolated from their neighbors.
+//
+// Detection is done over a run of frames. It works best when the camera
+// looks at a mostly uniform scene with a bit of variation, like a wall with
+// someone walking by.
+package badpixel
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"sort"
+
+	"github.com/maruel/go-lepton/gray14"
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// Kind is the reason a pixel is considered bad.
+type Kind string
+
+// Kinds of bad pixels.
+const (
+	// Dead is a pixel that always reads near zero.
+	Dead Kind = "dead"
+	// Stuck is a pixel which value never changes.
+	Stuck Kind = "stuck"
+	// Outlier is a pixel consistently much hotter or colder than its
+	// neighbors.
+	Outlier Kind = "outlier"
+	// Noisy is a pixel that fluctuates much more than the others.
+	Noisy Kind = "noisy"
+)
+
+// Pixel is a bad pixel.
+type Pixel struct {
+	X    int
+	Y    int
+	Kind Kind
+}
+
+// Map is the list of bad pixels of a camera.
+type Map struct {
+	// Serial is the serial number of the camera, as returned by GetSerial().
+	Serial uint64
+	// Bounds is the bounds of the frames the map applies to.
+	Bounds image.Rectangle
+	// Pixels must not be modified once the map was used to correct a frame.
+	Pixels []Pixel
+
+	bad map[image.Point]bool
+}
+
+// Path returns the path of the map for the camera serial in dir.
+func Path(dir string, serial uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("badpixels-%x.json", serial))
+}
+
+// Load loads a map saved with Save.
+func Load(path string) (*Map, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m := &Map{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	m.index()
+	return m, nil
+}
+
+// Save saves the map as a JSON file.
+func (m *Map) Save(path string) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(b, '\n'), 0600)
+}
+
+// Correct replaces the bad pixels of i in place with the median of their
+// good neighbors.
+//
+// It does nothing if the bounds of i don't match the map.
+func (m *Map) Correct(i *image14bit.Gray14) {
+	if len(m.Pixels) == 0 || i.Bounds() != m.Bounds {
+		return
+	}
+	if m.bad == nil {
+		m.index()
+	}
+	var values []image14bit.Intensity14
+	for _, p := range m.Pixels {
+		// Grow the neighborhood until good pixels are found.
+		for r := 1; r <= 3; r++ {
+			values = values[:0]
+			for dy := -r; dy <= r; dy++ {
+				for dx := -r; dx <= r; dx++ {
+					q := image.Point{p.X + dx, p.Y + dy}
+					if q.In(m.Bounds) && !m.bad[q] {
+						values = append(values, image14bit.Intensity14(i.Pix[i.PixOffset(q.X, q.Y)]))
+					}
+				}
+			}
+			if len(values) != 0 {
+				i.Pix[i.PixOffset(p.X, p.Y)] = uint16(gray14.MedianOf(values))
+				break
+			}
+		}
+	}
+}
+
+// DetectorOpts are the thresholds used by Detector.
+type DetectorOpts struct {
+	// DeadThreshold is the mean intensity under which a pixel is dead.
+	DeadThreshold float64
+	// StuckStdDev is the temporal standard deviation under which a pixel is
+	// stuck. It is only checked when the rest of the image varies.
+	StuckStdDev float64
+	// OutlierThreshold is the mean difference in counts with the median of
+	// the neighbors above which a pixel is an outlier.
+	OutlierThreshold float64
+	// NoisyFactor is the ratio of a pixel's temporal standard deviation over
+	// the median one above which a pixel is noisy.
+	NoisyFactor float64
+}
+
+// DefaultDetectorOpts are sensible defaults for a Lepton.
+var DefaultDetectorOpts = DetectorOpts{
+	DeadThreshold:    100,
+	StuckStdDev:      0.01,
+	OutlierThreshold: 400,
+	NoisyFactor:      6,
+}
+
+// Detector accumulates frames to find bad pixels.
+//
+// It is not safe for concurrent use.
+type Detector struct {
+	opts   DetectorOpts
+	bounds image.Rectangle
+	frames int
+	sum    []float64
+	sumSq  []float64
+	diff   []float64 // Sum of the difference with the median of the neighbors.
+}
+
+// NewDetector returns an initialized Detector. If opts is nil,
+// DefaultDetectorOpts is used.
+func NewDetector(opts *DetectorOpts) *Detector {
+	d := &Detector{opts: DefaultDetectorOpts}
+	if opts != nil {
+		d.opts = *opts
+	}
+	return d
+}
+
+// Frames returns the number of frames added so far.
+func (d *Detector) Frames() int {
+	return d.frames
+}
+
+// Add accumulates a frame. Frames with different bounds than the first one
+// are ignored.
+func (d *Detector) Add(i *image14bit.Gray14) {
+	b := i.Bounds()
+	if d.frames == 0 {
+		d.bounds = b
+		n := b.Dx() * b.Dy()
+		d.sum = make([]float64, n)
+		d.sumSq = make([]float64, n)
+		d.diff = make([]float64, n)
+	} else if b != d.bounds {
+		return
+	}
+	d.frames++
+	var values []image14bit.Intensity14
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			j := i.Pix[i.PixOffset(x, y)]
+			values = values[:0]
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					q := image.Point{x + dx, y + dy}
+					if (dx != 0 || dy != 0) && q.In(b) {
+						values = append(values, image14bit.Intensity14(i.Pix[i.PixOffset(q.X, q.Y)]))
+					}
+				}
+			}
+			n := (y-b.Min.Y)*b.Dx() + x - b.Min.X
+			v := float64(j)
+			d.sum[n] += v
+			d.sumSq[n] += v * v
+			if len(values) != 0 {
+				d.diff[n] += v - float64(gray14.MedianOf(values))
+			}
+		}
+	}
+}
+
+// Map returns the bad pixels found so far.
+func (d *Detector) Map(serial uint64) *Map {
+	m := &Map{Serial: serial, Bounds: d.bounds}
+	if d.frames == 0 {
+		return m
+	}
+	f := float64(d.frames)
+	stddev := make([]float64, len(d.sum))
+	for n := range d.sum {
+		mean := d.sum[n] / f
+		if v := d.sumSq[n]/f - mean*mean; v > 0 {
+			stddev[n] = math.Sqrt(v)
+		}
+	}
+	sorted := append([]float64(nil), stddev...)
+	sort.Float64s(sorted)
+	median := sorted[len(sorted)/2]
+	w := d.bounds.Dx()
+	for n := range d.sum {
+		mean := d.sum[n] / f
+		var k Kind
+		switch {
+		case mean < d.opts.DeadThreshold:
+			k = Dead
+		case d.frames > 1 && median > d.opts.StuckStdDev && stddev[n] < d.opts.StuckStdDev:
+			k = Stuck
+		case math.Abs(d.diff[n]/f) > d.opts.OutlierThreshold:
+			k = Outlier
+		case median > 0 && stddev[n] > d.opts.NoisyFactor*median:
+			k = Noisy
+		default:
+			continue
+		}
+		m.Pixels = append(m.Pixels, Pixel{X: d.bounds.Min.X + n%w, Y: d.bounds.Min.Y + n/w, Kind: k})
+	}
+	m.index()
+	return m
+}
+
+// Private details.
+
+// index builds the lookup of bad pixels used by Correct.
+func (m *Map) index() {
+	m.bad = make(map[image.Point]bool, len(m.Pixels))
+	for _, p := range m.Pixels {
+		m.bad[image.Point{p.X, p.Y}] = true
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package badpixel
+
+import (
+	"image"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+func TestDetector(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	d := NewDetector(nil)
+	for f := 0; f < 20; f++ {
+		i := image14bit.NewGray14(image.Rect(0, 0, 20, 15))
+		for n := range i.Pix {
+			i.Pix[n] = uint16(8000 + f + r.Intn(4))
+		}
+		i.SetIntensity14(1, 1, 0)
+		i.SetIntensity14(5, 5, 8100)
+		i.SetIntensity14(10, 10, 9000+image14bit.Intensity14(f))
+		i.SetIntensity14(15, 3, image14bit.Intensity14(8000+r.Intn(200)))
+		d.Add(i)
+	}
+	if d.Frames() != 20 {
+		t.Fatal(d.Frames())
+	}
+	m := d.Map(0x1234)
+	expected := []Pixel{{15, 3, Noisy}, {1, 1, Dead}, {5, 5, Stuck}, {10, 10, Outlier}}
+	got := map[Pixel]bool{}
+	for _, p := range m.Pixels {
+		got[p] = true
+	}
+	if len(m.Pixels) != len(expected) {
+		t.Fatal(m.Pixels)
+	}
+	for _, p := range expected {
+		if !got[p] {
+			t.Fatal(p, m.Pixels)
+		}
+	}
+}
+
+func TestMap(t *testing.T) {
+	m := &Map{Serial: 0x1234, Bounds: image.Rect(0, 0, 3, 3), Pixels: []Pixel{{1, 1, Dead}, {0, 0, Stuck}}}
+	i := image14bit.NewGray14(m.Bounds)
+	for n := range i.Pix {
+		i.Pix[n] = 8000
+	}
+	i.SetIntensity14(1, 1, 0)
+	i.SetIntensity14(0, 0, 16000)
+	m.Correct(i)
+	if i.Intensity14At(1, 1) != 8000 || i.Intensity14At(0, 0) != 8000 {
+		t.Fatal(i.Pix)
+	}
+
+	f, err := ioutil.TempFile("", "badpixel")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+	if err := m.Save(f.Name()); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m, got) {
+		t.Fatal(got)
+	}
+	if p := Path("foo", 0x1234); p != filepath.Join("foo", "badpixels-1234.json") {
+		t.Fatal(p)
+	}
+}
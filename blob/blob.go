@@ -0,0 +1,256 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package blob finds warm regions in frames.
+//
+// Pixels above a threshold are selected, the resulting mask is cleaned up
+// with morphological operations, then connected pixels are grouped into
+// blobs.
+package blob
+
+import (
+	"image"
+	"sort"
+
+	"github.com/maruel/go-lepton/radiometry"
+	"periph.io/x/periph/conn/physic"
+	"periph.io/x/periph/devices/lepton"
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// Opts controls how blobs are extracted.
+type Opts struct {
+	// Threshold is the minimum intensity for a pixel to be part of a blob.
+	Threshold image14bit.Intensity14
+	// MinArea is the minimum number of pixels of a blob. Smaller blobs are
+	// discarded.
+	MinArea int
+	// Open removes isolated pixels and thin protrusions from the mask with a
+	// 3x3 opening.
+	Open bool
+	// Close fills single pixel holes and gaps in the mask with a 3x3 closing.
+	Close bool
+	// Diagonal connects pixels that only touch by a corner.
+	Diagonal bool
+}
+
+// DefaultOpts is the default options. Threshold must be set.
+var DefaultOpts = Opts{MinArea: 4, Open: true, Close: true, Diagonal: true}
+
+// Point is a sub-pixel position.
+type Point struct {
+	X float64
+	Y float64
+}
+
+// Blob is a group of connected pixels.
+type Blob struct {
+	// Label is the value of the blob's pixels in the labels image, starting
+	// at 1.
+	Label int
+	// Area is the number of pixels.
+	Area int
+	// Bounds is the smallest rectangle containing the blob.
+	Bounds image.Rectangle
+	// Centroid is the average position of the pixels, at the pixel centers.
+	Centroid Point
+	// Peak is the maximum intensity and PeakPos its position.
+	Peak    image14bit.Intensity14
+	PeakPos image.Point
+	// Mean is the average intensity.
+	Mean float64
+}
+
+// Detect returns the blobs of pixels at or above opts.Threshold, largest
+// first.
+//
+// If opts is nil, DefaultOpts is used.
+func Detect(i *image14bit.Gray14, opts *Opts) []Blob {
+	o := DefaultOpts
+	if opts != nil {
+		o = *opts
+	}
+	return FromMask(Threshold(i, o.Threshold), i, &o)
+}
+
+// DetectTemperature returns the blobs of pixels at or above the temperature
+// t, converted to counts with the calibration c for this frame.
+//
+// opts.Threshold is ignored.
+func DetectTemperature(f *lepton.Frame, c *radiometry.Calibration, t physic.Temperature, opts *Opts) ([]Blob, error) {
+	l, err := c.ForFrame(&f.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	o := DefaultOpts
+	if opts != nil {
+		o = *opts
+	}
+	o.Threshold = l.Count(t)
+	return Detect(f.Gray14, &o), nil
+}
+
+// FromMask returns the blobs of the non-zero pixels of mask, largest first.
+// Statistics are taken from i, which must have the same bounds.
+//
+// The threshold in opts is ignored. This is useful to extract blobs from a
+// mask computed otherwise, like a foreground mask.
+func FromMask(mask *image.Gray, i *image14bit.Gray14, opts *Opts) []Blob {
+	o := DefaultOpts
+	if opts != nil {
+		o = *opts
+	}
+	if o.Open {
+		mask = Dilate(Erode(mask))
+	}
+	if o.Close {
+		mask = Erode(Dilate(mask))
+	}
+	labels, n := Label(mask, o.Diagonal)
+	blobs := make([]Blob, n)
+	sums := make([]Point, n)
+	totals := make([]float64, n)
+	b := mask.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			l := labels[(y-b.Min.Y)*b.Dx()+x-b.Min.X]
+			if l == 0 {
+				continue
+			}
+			bl := &blobs[l-1]
+			v := i.Intensity14At(x, y)
+			p := image.Pt(x, y)
+			if bl.Area == 0 {
+				bl.Label = l
+				bl.Bounds = image.Rectangle{p, p.Add(image.Pt(1, 1))}
+				bl.Peak = v
+				bl.PeakPos = p
+			} else {
+				bl.Bounds = bl.Bounds.Union(image.Rectangle{p, p.Add(image.Pt(1, 1))})
+				if v > bl.Peak {
+					bl.Peak = v
+					bl.PeakPos = p
+				}
+			}
+			bl.Area++
+			sums[l-1].X += float64(x) + 0.5
+			sums[l-1].Y += float64(y) + 0.5
+			totals[l-1] += float64(v)
+		}
+	}
+	out := blobs[:0]
+	for n := range blobs {
+		bl := blobs[n]
+		if bl.Area < o.MinArea {
+			continue
+		}
+		bl.Centroid = Point{sums[n].X / float64(bl.Area), sums[n].Y / float64(bl.Area)}
+		bl.Mean = totals[n] / float64(bl.Area)
+		out = append(out, bl)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Area > out[j].Area })
+	return out
+}
+
+// Threshold returns a mask where the pixels at or above t are 255 and the
+// others 0.
+func Threshold(i *image14bit.Gray14, t image14bit.Intensity14) *image.Gray {
+	b := i.Bounds()
+	mask := image.NewGray(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if i.Intensity14At(x, y) >= t {
+				mask.Pix[mask.PixOffset(x, y)] = 255
+			}
+		}
+	}
+	return mask
+}
+
+// Erode returns a copy of mask where a pixel is kept only if all its
+// neighbors in a 3x3 window are set. Pixels outside the image are ignored.
+func Erode(mask *image.Gray) *image.Gray {
+	return morph(mask, true)
+}
+
+// Dilate returns a copy of mask where a pixel is set if any of its neighbors
+// in a 3x3 window is set.
+func Dilate(mask *image.Gray) *image.Gray {
+	return morph(mask, false)
+}
+
+// Label labels the connected components of the non-zero pixels of mask.
+//
+// It returns the label of each pixel in row order, 0 for the background and
+// starting at 1 for the components, and the number of components. When
+// diagonal is true, pixels touching by a corner are connected.
+func Label(mask *image.Gray, diagonal bool) ([]int, int) {
+	b := mask.Bounds()
+	w := b.Dx()
+	labels := make([]int, w*b.Dy())
+	n := 0
+	var stack []image.Point
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if mask.Pix[mask.PixOffset(x, y)] == 0 || labels[(y-b.Min.Y)*w+x-b.Min.X] != 0 {
+				continue
+			}
+			// Flood fill a new component.
+			n++
+			labels[(y-b.Min.Y)*w+x-b.Min.X] = n
+			stack = append(stack[:0], image.Pt(x, y))
+			for len(stack) != 0 {
+				p := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				for dy := -1; dy <= 1; dy++ {
+					for dx := -1; dx <= 1; dx++ {
+						if (dx == 0 && dy == 0) || (!diagonal && dx != 0 && dy != 0) {
+							continue
+						}
+						q := image.Pt(p.X+dx, p.Y+dy)
+						if !q.In(b) || mask.Pix[mask.PixOffset(q.X, q.Y)] == 0 {
+							continue
+						}
+						if o := (q.Y-b.Min.Y)*w + q.X - b.Min.X; labels[o] == 0 {
+							labels[o] = n
+							stack = append(stack, q)
+						}
+					}
+				}
+			}
+		}
+	}
+	return labels, n
+}
+
+// Private details.
+
+// morph implements a 3x3 erosion if erode is true, dilation otherwise.
+func morph(mask *image.Gray, erode bool) *image.Gray {
+	b := mask.Bounds()
+	dst := image.NewGray(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			// With erosion, a pixel stays set unless a neighbor is clear. With
+			// dilation, a pixel becomes set if a neighbor is set.
+			set := erode
+			for dy := -1; dy <= 1 && set == erode; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					q := image.Pt(x+dx, y+dy)
+					if !q.In(b) {
+						continue
+					}
+					if (mask.Pix[mask.PixOffset(q.X, q.Y)] != 0) != erode {
+						set = !erode
+						break
+					}
+				}
+			}
+			if set {
+				dst.Pix[dst.PixOffset(x, y)] = 255
+			}
+		}
+	}
+	return dst
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package blob
+
+import (
+	"image"
+	"testing"
+
+	"github.com/maruel/go-lepton/radiometry"
+	"periph.io/x/periph/conn/physic"
+	"periph.io/x/periph/devices/lepton"
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+func TestDetect(t *testing.T) {
+	i := scene()
+	blobs := Detect(i, &Opts{Threshold: 8500, MinArea: 4, Open: true, Close: true, Diagonal: true})
+	if len(blobs) != 2 {
+		t.Fatal(blobs)
+	}
+	// The hole in the large blob is closed and the noise pixel is removed.
+	b := blobs[0]
+	if b.Area != 100 || b.Bounds != image.Rect(10, 10, 20, 20) {
+		t.Fatal(b)
+	}
+	if b.Centroid != (Point{15, 15}) {
+		t.Fatal(b.Centroid)
+	}
+	if b.Peak != 9500 || b.PeakPos != image.Pt(12, 13) {
+		t.Fatal(b)
+	}
+	b = blobs[1]
+	if b.Area != 9 || b.Bounds != image.Rect(50, 40, 53, 43) || b.Peak != 8800 || b.Mean != 8800 {
+		t.Fatal(b)
+	}
+}
+
+func TestDetect_NoMorphology(t *testing.T) {
+	i := scene()
+	blobs := Detect(i, &Opts{Threshold: 8500, MinArea: 1})
+	if len(blobs) != 3 {
+		t.Fatal(blobs)
+	}
+	if blobs[0].Area != 99 || blobs[2].Area != 1 || blobs[2].Bounds != image.Rect(70, 5, 71, 6) {
+		t.Fatal(blobs)
+	}
+}
+
+func TestDetectTemperature(t *testing.T) {
+	f := &lepton.Frame{Gray14: scene()}
+	f.Metadata.TempHousing = physic.ZeroCelsius + 25*physic.Kelvin
+	c := radiometry.Calibration{TLinear: true, Resolution: 10 * physic.MilliKelvin}
+	// 9000 counts are 90K in TLinear with 10mK resolution, 8800 are 88K.
+	blobs, err := DetectTemperature(f, &c, 89*physic.Kelvin, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blobs) != 1 || blobs[0].Area != 100 {
+		t.Fatal(blobs)
+	}
+}
+
+func TestLabel(t *testing.T) {
+	mask := image.NewGray(image.Rect(0, 0, 3, 3))
+	mask.Pix = []uint8{
+		255, 0, 0,
+		0, 255, 0,
+		0, 0, 255,
+	}
+	if _, n := Label(mask, true); n != 1 {
+		t.Fatal(n)
+	}
+	labels, n := Label(mask, false)
+	if n != 3 {
+		t.Fatal(n)
+	}
+	if labels[0] != 1 || labels[4] != 2 || labels[8] != 3 || labels[1] != 0 {
+		t.Fatal(labels)
+	}
+}
+
+// scene returns a frame with a 10x10 blob with a hole, a 3x3 blob and an
+// isolated noisy pixel.
+func scene() *image14bit.Gray14 {
+	i := image14bit.NewGray14(image.Rect(0, 0, 80, 60))
+	for n := range i.Pix {
+		i.Pix[n] = 8000
+	}
+	for y := 10; y < 20; y++ {
+		for x := 10; x < 20; x++ {
+			i.SetIntensity14(x, y, 9000)
+		}
+	}
+	i.SetIntensity14(15, 15, 8000)
+	i.SetIntensity14(12, 13, 9500)
+	for y := 40; y < 43; y++ {
+		for x := 50; x < 53; x++ {
+			i.SetIntensity14(x, y, 8800)
+		}
+	}
+	i.SetIntensity14(70, 5, 9999)
+	return i
+}
@@ -5,14 +5,24 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
+	"os/user"
+	"path/filepath"
 	"runtime/pprof"
+	"strings"
+	"time"
 
+	"github.com/maruel/go-lepton/counting"
+	"github.com/maruel/go-lepton/fall"
+	"github.com/maruel/go-lepton/gray14"
 	"github.com/maruel/go-lepton/leptontest"
+	"github.com/maruel/go-lepton/radiometry"
+	"github.com/maruel/go-lepton/recording"
 	"github.com/maruel/interrupt"
 	"periph.io/x/periph/conn/i2c/i2creg"
 	"periph.io/x/periph/conn/spi/spireg"
@@ -29,11 +39,32 @@ func mainImpl() error {
 	fake := flag.Bool("fake", false, "use a fake camera mock, useful to test without the hardware")
 	i2cName := flag.String("i2c", "", "I²C bus to use")
 	spiName := flag.String("spi", "", "SPI bus to use")
+	palette := flag.String("palette", "default", "default palette to use when rendering images")
+	denoise := flag.String("denoise", "", "denoiser to apply when rendering images; one of: "+strings.Join(gray14.DenoiserNames(), ", "))
+	tlinear := flag.Bool("tlinear", false, "the camera is configured with TLinear enabled, for radiometric measurements")
+	var isos isotherms
+	flag.Var(&isos, "isotherm", "isotherm band to highlight as LOW..HIGH:RRGGBB[AA] where LOW and HIGH are counts or temperatures like 45C; can be repeated")
+	legend := flag.Bool("legend", false, "add a colorbar with labels and a min/max/mean readout to rendered images")
+	overlay := flag.Bool("overlay", false, "draw a crosshair, the min/max markers, the timestamp and the camera name on rendered images")
+	camera := flag.String("name", hostname(), "name of the camera printed in the overlay")
+	units := flag.String("units", "C", "units of the printed values: C, K or counts")
+	trackLevel := flag.String("track", "", "follow the objects warmer than this count or temperature like 30C, or in front of the background with \"foreground\"")
+	countingConfig := flag.String("counting", "", "JSON file with the lines and zones to count the tracked objects on; requires -track")
+	fallDuration := flag.Duration("fall", 0, "alert when a tracked person is down and still for this long, e.g. 10s; requires -track")
+	record := flag.String("record", "", "append the frames as read from the camera, before any correction or filtering, to this recording file, usually with the .lrec extension")
+	temporal := flag.Bool("temporal", false, "apply temporal noise reduction to all frames")
+	nucFrames := flag.Int("nuc", 0, "run a FFC then calibrate the NUC over this number of frames; the camera must look at a uniform target")
+	nucHot := flag.Duration("nuc-hot", 0, "with -nuc, wait this long after the first reference for the camera to be pointed at a hotter uniform target, then capture a second reference for a two point NUC")
+	detectBadPixels := flag.Int("detect-badpixels", 0, "detect bad pixels over this number of frames and save the map for this camera")
+	palettesDir := flag.String("palettes", filepath.Join(configDir(), "palettes"), "directory containing custom palettes as JSON files")
 	flag.Parse()
 
 	if len(flag.Args()) != 0 {
 		return fmt.Errorf("unexpected argument: %s", flag.Args())
 	}
+	if *nucHot != 0 && *nucFrames <= 0 {
+		return errors.New("-nuc-hot requires -nuc")
+	}
 
 	if !*verbose {
 		log.SetOutput(ioutil.Discard)
@@ -48,6 +79,25 @@ func mainImpl() error {
 		defer pprof.StopCPUProfile()
 	}
 
+	if err := loadPalettes(*palettesDir); err != nil {
+		return err
+	}
+	if gray14.PaletteByName(*palette) == nil {
+		return fmt.Errorf("unknown palette %q; valid palettes are: %s", *palette, strings.Join(gray14.PaletteNames(), ", "))
+	}
+
+	switch *units {
+	case "C", "K", "counts":
+	default:
+		return fmt.Errorf("invalid units %q; expected C, K or counts", *units)
+	}
+
+	if *denoise != "" {
+		if _, err := gray14.DenoiserByName(*denoise); err != nil {
+			return err
+		}
+	}
+
 	interrupt.HandleCtrlC()
 
 	if _, err := host.Init(); err != nil {
@@ -79,22 +129,79 @@ func mainImpl() error {
 		s = LoadSeeder()
 	}
 
-	c := make(chan *lepton.Frame, 9*60)
+	c := make(chan capture, 9*60)
 	var d chan *lepton.Frame
 	if s != nil {
 		d = make(chan *lepton.Frame, 9*60)
 	}
 
+	serial, err := dev.GetSerial()
+	if err != nil {
+		return err
+	}
+	calib := radiometry.Default
+	calib.TLinear = *tlinear
+	p, err := newPipeline(configDir(), serial, *nucFrames, *nucHot, *detectBadPixels, *temporal)
+	if err != nil {
+		return err
+	}
+	if *trackLevel != "" {
+		if err := p.enableTracking(*trackLevel, calib); err != nil {
+			return err
+		}
+	}
+	if *countingConfig != "" {
+		cfg, err := counting.LoadConfig(*countingConfig)
+		if err != nil {
+			return err
+		}
+		if err := p.enableCounting(cfg); err != nil {
+			return err
+		}
+	}
+	if *fallDuration != 0 {
+		o := fall.DefaultOpts
+		o.Duration = *fallDuration
+		if err := p.enableFall(&o); err != nil {
+			return err
+		}
+	}
+	var rec *recording.Writer
+	if *record != "" {
+		if _, err = os.Stat(*record); err == nil {
+			rec, err = recording.Append(*record, dev.Bounds(), serial, nil)
+		} else {
+			rec, err = recording.Create(*record, dev.Bounds(), serial, nil)
+		}
+		if err != nil {
+			return err
+		}
+		defer rec.Close()
+	}
+	if *nucFrames > 0 {
+		if err := dev.RunFFC(); err != nil {
+			return err
+		}
+	}
+
 	// Lepton reader loop.
 	go func() {
 		for {
 			// Keep this loop busy to not lose sync on SPI.
 			b := image14bit.NewGray14(dev.Bounds())
 			f := &lepton.Frame{Gray14: b}
+			var raw *lepton.Frame
 			if err := dev.NextFrame(f); err != nil {
 				log.Printf("%v", err)
+			} else if rec != nil {
+				// The pipeline modifies the frame in place; record the original.
+				raw = &lepton.Frame{Gray14: image14bit.NewGray14(b.Rect), Metadata: f.Metadata}
+				copy(raw.Pix, b.Pix)
 			}
-			c <- f
+			r := p.process(f)
+			r.Time = time.Now()
+			r.Raw = raw
+			c <- r
 			if d != nil {
 				d <- f
 			}
@@ -102,10 +209,22 @@ func mainImpl() error {
 	}()
 
 	//w := StartWebServer(dev, c, *port)
-	w := StartWebServer(*port)
+	opts := &renderOpts{palette: *palette, denoiser: *denoise, calib: calib, isotherms: isos, legend: *legend, overlay: *overlay, camera: *camera, defaultUnits: *units}
+	w := StartWebServer(*port, opts, p)
 	go func() {
 		for {
-			w.AddImg(<-c)
+			r := <-c
+			if r.Counted {
+				if err := p.saveCounts(); err != nil {
+					log.Printf("failed to save counts: %v", err)
+				}
+			}
+			if r.Raw != nil {
+				if err := rec.Write(r.Raw, r.Time); err != nil {
+					log.Printf("recording: %v", err)
+				}
+			}
+			w.AddImg(r)
 		}
 	}()
 	if d != nil {
@@ -116,6 +235,43 @@ func mainImpl() error {
 	return watchFile()
 }
 
+// hostname returns the host name, used as the default camera name.
+func hostname() string {
+	h, _ := os.Hostname()
+	return h
+}
+
+// configDir returns ~/.config/lepton.
+func configDir() string {
+	usr, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(usr.HomeDir, ".config", "lepton")
+}
+
+// loadPalettes registers all the *.json palettes found in dir.
+func loadPalettes(dir string) error {
+	if dir == "" {
+		return nil
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		p, err := gray14.LoadPalette(f)
+		if err != nil {
+			return err
+		}
+		if err := gray14.RegisterPalette(p); err != nil {
+			return err
+		}
+		log.Printf("Loaded palette %s from %s", p.Name, f)
+	}
+	return nil
+}
+
 func main() {
 	if err := mainImpl(); err != nil {
 		fmt.Fprintf(os.Stderr, "\nlepton: %s.\n", err)
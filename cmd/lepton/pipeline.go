@@ -0,0 +1,278 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/maruel/go-lepton/background"
+	"github.com/maruel/go-lepton/badpixel"
+	"github.com/maruel/go-lepton/blob"
+	"github.com/maruel/go-lepton/counting"
+	"github.com/maruel/go-lepton/fall"
+	"github.com/maruel/go-lepton/gray14"
+	"github.com/maruel/go-lepton/nuc"
+	"github.com/maruel/go-lepton/radiometry"
+	"github.com/maruel/go-lepton/track"
+	"periph.io/x/periph/conn/physic"
+	"periph.io/x/periph/devices/lepton"
+	"periph.io/x/periph/devices/lepton/cci"
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// pipeline is the processing applied to every frame read from the camera,
+// before it is sent to the consumers.
+//
+// It is only used from the reader loop, except for counter which is safe for
+// concurrent use and saveCounts.
+type pipeline struct {
+	serial       uint64
+	dir          string // Where per-camera calibration data is stored.
+	nuc          *nuc.Correction
+	nucRef       *nuc.Reference
+	nucFrames    int
+	nucCold      *nuc.Reference // First reference of a two point NUC.
+	nucHot       time.Duration  // Delay before the second reference, if any.
+	nucWait      time.Time      // When to start the second reference.
+	badPixels    *badpixel.Map
+	detector     *badpixel.Detector
+	detectFrames int
+	temporal     *gray14.Temporal
+	tracking     *tracking
+	counter      *counting.Counter
+	fall         *fall.Detector
+}
+
+// capture is a frame read from the camera with the results of its
+// processing.
+type capture struct {
+	*lepton.Frame
+	// Time is when the frame was read.
+	Time time.Time
+	// Tracks are the objects followed, if tracking is enabled.
+	Tracks []track.Track
+	// Fallen are the IDs of the tracks of the people currently fallen, if
+	// fall detection is enabled.
+	Fallen []int
+	// Counted is true when the counting totals changed with this frame. They
+	// are saved by the consumer to keep the reader loop busy.
+	Counted bool
+	// Raw is the frame as read from the camera, before the pipeline modified
+	// it. It is only set when recording and the frame was read successfully.
+	Raw *lepton.Frame
+}
+
+// tracking detects warm objects and follows them.
+type tracking struct {
+	temperature bool  // level is a temperature instead of a count.
+	level       int64 // As returned by parseLevel.
+	calib       radiometry.Calibration
+	model       *background.Model // Set to detect the foreground instead.
+	tracker     *track.Tracker
+	failed      bool // Detection failed once, to not spam the logs.
+}
+
+// newPipeline loads the calibration data for the camera serial from dir.
+//
+// If nucFrames is not 0, a NUC reference is captured over this number of
+// frames and the correction is saved. The camera is expected to look at a
+// uniform target. If nucHot is not 0, a second reference of a hotter uniform
+// target is captured nucHot after the first one for a two point correction.
+//
+// If detectFrames is not 0, the bad pixels are detected over this number of
+// frames and the map is saved.
+func newPipeline(dir string, serial uint64, nucFrames int, nucHot time.Duration, detectFrames int, temporal bool) (*pipeline, error) {
+	p := &pipeline{serial: serial, dir: dir, nucFrames: nucFrames, nucHot: nucHot, detectFrames: detectFrames}
+	if nucFrames > 0 {
+		p.nucRef = &nuc.Reference{}
+	} else {
+		c, err := nuc.Load(nuc.Path(dir, serial))
+		if err == nil {
+			log.Printf("Loaded NUC")
+			p.nuc = c
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	if detectFrames > 0 {
+		p.detector = badpixel.NewDetector(nil)
+	} else {
+		m, err := badpixel.Load(badpixel.Path(dir, serial))
+		if err == nil {
+			log.Printf("Loaded %d bad pixels", len(m.Pixels))
+			p.badPixels = m
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	if temporal {
+		p.temporal = gray14.NewTemporal(nil)
+	}
+	return p, nil
+}
+
+// enableTracking follows the objects warmer than level, which is a count or
+// a temperature as accepted by parseLevel, or the objects in front of the
+// learned background if level is "foreground".
+func (p *pipeline) enableTracking(level string, calib radiometry.Calibration) error {
+	if level == "foreground" {
+		p.tracking = &tracking{model: background.New(nil), tracker: track.New(nil)}
+		return nil
+	}
+	temperature, v, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+	p.tracking = &tracking{temperature: temperature, level: v, calib: calib, tracker: track.New(nil)}
+	return nil
+}
+
+// enableCounting counts the tracked objects crossing the lines and zones in
+// c. The totals of the camera are loaded and saved in the pipeline's
+// directory.
+func (p *pipeline) enableCounting(c *counting.Config) error {
+	if p.tracking == nil {
+		return errors.New("counting requires tracking")
+	}
+	p.counter = counting.New(c)
+	if err := p.counter.Load(counting.Path(p.dir, p.serial)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// enableFall raises alerts when the tracked people fall.
+func (p *pipeline) enableFall(opts *fall.Opts) error {
+	if p.tracking == nil {
+		return errors.New("fall detection requires tracking")
+	}
+	p.fall = fall.New(opts)
+	return nil
+}
+
+// saveCounts saves the totals of the counter.
+func (p *pipeline) saveCounts() error {
+	if err := os.MkdirAll(p.dir, 0700); err != nil {
+		return err
+	}
+	return p.counter.Save(counting.Path(p.dir, p.serial))
+}
+
+// process modifies f in place and returns it with the results of the
+// analysis. Time is left to be filled by the caller.
+func (p *pipeline) process(f *lepton.Frame) capture {
+	out := capture{Frame: f}
+	if p.nucRef != nil {
+		if !p.nucWait.IsZero() {
+			if time.Now().Before(p.nucWait) {
+				return out
+			}
+			p.nucWait = time.Time{}
+			fmt.Printf("Capturing the hot NUC reference\n")
+		}
+		// Calibration must be done on raw frames, and the frames during a FFC
+		// are not representative.
+		if f.Metadata.FFCState != cci.FFCInProgress {
+			p.nucRef.Add(f.Gray14)
+		}
+		if p.nucRef.Frames() == p.nucFrames {
+			if p.nucHot > 0 && p.nucCold == nil {
+				p.nucCold = p.nucRef
+				p.nucRef = &nuc.Reference{}
+				p.nucWait = time.Now().Add(p.nucHot)
+				fmt.Printf("Cold NUC reference captured; point the camera at a hotter uniform target within %s\n", p.nucHot)
+				return out
+			}
+			var c *nuc.Correction
+			var err error
+			if p.nucCold != nil {
+				c, err = nuc.TwoPoint(p.serial, p.nucCold, p.nucRef)
+			} else {
+				c, err = nuc.OnePoint(p.serial, p.nucRef)
+			}
+			p.nucRef = nil
+			p.nucCold = nil
+			if err != nil {
+				log.Printf("failed to calculate NUC: %v", err)
+				return out
+			}
+			p.nuc = c
+			if err := os.MkdirAll(p.dir, 0700); err != nil {
+				log.Printf("failed to create %s: %v", p.dir, err)
+			} else if err := c.Save(nuc.Path(p.dir, p.serial)); err != nil {
+				log.Printf("failed to save NUC: %v", err)
+			}
+			fmt.Printf("NUC calibrated\n")
+		}
+		return out
+	}
+	if p.nuc != nil {
+		p.nuc.Apply(f.Gray14)
+	}
+	if p.detector != nil {
+		p.detector.Add(f.Gray14)
+		if p.detector.Frames() == p.detectFrames {
+			p.badPixels = p.detector.Map(p.serial)
+			p.detector = nil
+			if err := os.MkdirAll(p.dir, 0700); err != nil {
+				log.Printf("failed to create %s: %v", p.dir, err)
+			} else if err := p.badPixels.Save(badpixel.Path(p.dir, p.serial)); err != nil {
+				log.Printf("failed to save bad pixels: %v", err)
+			}
+			fmt.Printf("Found %d bad pixels\n", len(p.badPixels.Pixels))
+		}
+	}
+	if p.badPixels != nil {
+		p.badPixels.Correct(f.Gray14)
+	}
+	if p.temporal != nil {
+		p.temporal.Filter(f.Gray14)
+	}
+	if p.tracking == nil {
+		return out
+	}
+	out.Tracks = p.tracking.update(f)
+	if p.counter != nil {
+		if events := p.counter.Update(out.Tracks); len(events) != 0 {
+			for _, e := range events {
+				log.Printf("%s: object %d went %s", e.Name, e.TrackID, e.Direction)
+			}
+			out.Counted = true
+		}
+	}
+	if p.fall != nil {
+		for _, e := range p.fall.Update(f, out.Tracks) {
+			log.Printf("person %d %s at %.0f,%.0f", e.TrackID, e.Kind, e.Pos.X, e.Pos.Y)
+		}
+		out.Fallen = p.fall.Fallen()
+	}
+	return out
+}
+
+// update detects the objects in f and returns the updated tracks.
+func (t *tracking) update(f *lepton.Frame) []track.Track {
+	var blobs []blob.Blob
+	if t.model != nil {
+		blobs = blob.FromMask(t.model.Update(f.Gray14), f.Gray14, nil)
+	} else if t.temperature {
+		var err error
+		if blobs, err = blob.DetectTemperature(f, &t.calib, physic.Temperature(t.level), nil); err != nil {
+			if !t.failed {
+				log.Printf("tracking: %v", err)
+				t.failed = true
+			}
+		}
+	} else {
+		o := blob.DefaultOpts
+		o.Threshold = image14bit.Intensity14(t.level)
+		blobs = blob.Detect(f.Gray14, &o)
+	}
+	active, _ := t.tracker.Update(&f.Metadata, blobs)
+	return active
+}
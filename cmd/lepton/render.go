@@ -0,0 +1,351 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maruel/go-lepton/annotate"
+	"github.com/maruel/go-lepton/gray14"
+	"github.com/maruel/go-lepton/radiometry"
+	"periph.io/x/periph/conn/physic"
+	"periph.io/x/periph/devices/lepton"
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// renderOpts are the defaults used to render images server side.
+type renderOpts struct {
+	palette   string // Name of the default palette.
+	denoiser  string // Name of the default denoiser, if any.
+	calib     radiometry.Calibration
+	isotherms isotherms
+	legend    bool   // Add a colorbar and a readout by default.
+	overlay   bool   // Draw the annotations by default.
+	camera    string // Name of the camera printed in the overlay.
+	// defaultUnits are the units of the printed values: "C", "K" or "counts".
+	defaultUnits string
+}
+
+// render renders the frame as requested in the query:
+//   - ?palette= selects the palette.
+//   - ?denoise= selects a denoiser, "none" disables the default one.
+//   - ?width= and ?height= resize the image; if only one is specified, the
+//     aspect ratio is kept. ?filter= selects the resampling filter.
+//   - ?isotherm= adds an isotherm band and can be repeated. It replaces the
+//     default ones. See isotherms.Set for the format.
+//   - ?legend=1 adds a colorbar with labels and a min/max/mean readout, 0
+//     removes the default one. ?units= selects the units of the labels as
+//     "C", "K" or "counts". Temperatures fall back to counts when the frame
+//     has no telemetry.
+//   - ?overlay=1 draws a crosshair, the min/max markers, the timestamp t and
+//     the camera name, 0 removes the default ones. ?spot=X,Y prints the value
+//     of a pixel and ?roi= outlines a gray14.Region encoded as JSON. Both can
+//     be repeated. Coordinates are in frame pixels and a ROI must be inside
+//     the frame.
+func (o *renderOpts) render(r *http.Request, img *lepton.Frame, t time.Time) (*image.NRGBA, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	p, err := o.getPalette(r)
+	if err != nil {
+		return nil, err
+	}
+	specs := o.isotherms
+	if v := r.Form["isotherm"]; len(v) != 0 {
+		specs = nil
+		for _, s := range v {
+			if err := specs.Set(s); err != nil {
+				return nil, err
+			}
+		}
+	}
+	bands, err := o.resolveIsotherms(specs, &img.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	denoised, err := o.denoise(r, img.Gray14)
+	if err != nil {
+		return nil, err
+	}
+	src, err := resize(r, denoised)
+	if err != nil {
+		return nil, err
+	}
+	agc := gray14.AGCLinear(src)
+	var dst *image.NRGBA
+	if len(bands) != 0 {
+		dst = gray14.Isotherms(src, agc, p, bands)
+	} else {
+		dst = p.Colorize(agc)
+	}
+	linear, kelvin, err := o.units(r, &img.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	// Scale the font with the image so the labels stay readable.
+	scale := 1 + dst.Bounds().Dy()/240
+	stats := gray14.Stats(denoised, nil)
+
+	ov := annotate.Overlay{Linear: linear, Kelvin: kelvin, Scale: scale}
+	showOverlay := o.overlay
+	if v := r.FormValue("overlay"); v != "" {
+		if showOverlay, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("invalid overlay %q", v)
+		}
+	}
+	if showOverlay {
+		ov.Crosshair = true
+		ov.MinMax = true
+		ov.Timestamp = t
+		ov.Camera = o.camera
+	}
+	for _, v := range r.Form["spot"] {
+		var p image.Point
+		if _, err := fmt.Sscanf(v, "%d,%d", &p.X, &p.Y); err != nil {
+			return nil, fmt.Errorf("invalid spot %q; expected X,Y", v)
+		}
+		ov.Spots = append(ov.Spots, p)
+	}
+	for _, v := range r.Form["roi"] {
+		var reg gray14.Region
+		if err := json.Unmarshal([]byte(v), &reg); err != nil {
+			return nil, fmt.Errorf("invalid roi %q: %v", v, err)
+		}
+		if !reg.ROI.Bounds().In(denoised.Bounds()) {
+			return nil, fmt.Errorf("invalid roi %q; it must be inside the frame %s", v, denoised.Bounds())
+		}
+		ov.Regions = append(ov.Regions, reg)
+	}
+	ov.Draw(dst, denoised, stats)
+
+	legend := o.legend
+	if v := r.FormValue("legend"); v != "" {
+		if legend, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("invalid legend %q", v)
+		}
+	}
+	if !legend {
+		return dst, nil
+	}
+	lo := annotate.LegendOpts{Palette: p, Linear: linear, Kelvin: kelvin, Ticks: annotate.DefaultLegendOpts.Ticks, Scale: scale}
+	return annotate.Legend(dst, gray14.Min(src), gray14.Max(src), stats, &lo), nil
+}
+
+// units returns the transfer function to use for the printed values, nil for
+// raw counts, and whether to use Kelvin.
+func (o *renderOpts) units(r *http.Request, m *lepton.Metadata) (*radiometry.Linear, bool, error) {
+	units := r.FormValue("units")
+	if units == "" {
+		units = o.defaultUnits
+	}
+	switch units {
+	case "counts":
+		return nil, false, nil
+	case "", "C", "K":
+	default:
+		return nil, false, fmt.Errorf("invalid units %q", units)
+	}
+	l, err := o.calib.ForFrame(m)
+	if err != nil {
+		return nil, false, nil
+	}
+	return &l, units == "K", nil
+}
+
+// getPalette returns the palette requested in the query, or the default one.
+func (o *renderOpts) getPalette(r *http.Request) (*gray14.Palette, error) {
+	name := r.FormValue("name")
+	if name == "" {
+		name = r.FormValue("palette")
+	}
+	if name == "" {
+		name = o.palette
+	}
+	if p := gray14.PaletteByName(name); p != nil {
+		return p, nil
+	}
+	return nil, fmt.Errorf("unknown palette %q", name)
+}
+
+// denoise applies the denoiser requested in the query, or the default one.
+func (o *renderOpts) denoise(r *http.Request, img *image14bit.Gray14) (*image14bit.Gray14, error) {
+	name := r.FormValue("denoise")
+	if name == "" {
+		name = o.denoiser
+	}
+	if name == "" || name == "none" {
+		return img, nil
+	}
+	d, err := gray14.DenoiserByName(name)
+	if err != nil {
+		return nil, err
+	}
+	return d(img), nil
+}
+
+// resolveIsotherms converts the isotherms into counts for a frame.
+func (o *renderOpts) resolveIsotherms(specs isotherms, m *lepton.Metadata) ([]gray14.Isotherm, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+	var l radiometry.Linear
+	out := make([]gray14.Isotherm, len(specs))
+	for i, s := range specs {
+		out[i].Color = s.color
+		if !s.temperature {
+			out[i].Low = s.lowCount
+			out[i].High = s.highCount
+			continue
+		}
+		if l.Gain == 0 {
+			var err error
+			if l, err = o.calib.ForFrame(m); err != nil {
+				return nil, err
+			}
+		}
+		out[i].Low = l.Count(s.lowTemp)
+		out[i].High = l.Count(s.highTemp)
+	}
+	return out, nil
+}
+
+// resize resizes the raw image as requested in the query.
+func resize(r *http.Request, img *image14bit.Gray14) (*image14bit.Gray14, error) {
+	b := img.Bounds()
+	width, height := 0, 0
+	var err error
+	if v := r.FormValue("width"); v != "" {
+		if width, err = strconv.Atoi(v); err != nil || width <= 0 || width > 4096 {
+			return nil, fmt.Errorf("invalid width %q", v)
+		}
+	}
+	if v := r.FormValue("height"); v != "" {
+		if height, err = strconv.Atoi(v); err != nil || height <= 0 || height > 4096 {
+			return nil, fmt.Errorf("invalid height %q", v)
+		}
+	}
+	if width == 0 && height == 0 {
+		return img, nil
+	}
+	if width == 0 {
+		width = (height*b.Dx() + b.Dy()/2) / b.Dy()
+	} else if height == 0 {
+		height = (width*b.Dy() + b.Dx()/2) / b.Dx()
+	}
+	f := gray14.Bicubic
+	if v := r.FormValue("filter"); v != "" {
+		if f, err = gray14.ParseFilter(v); err != nil {
+			return nil, err
+		}
+	}
+	return gray14.Resize(img, width, height, f), nil
+}
+
+// isotherm is an isotherm band as specified by the user, either in counts or
+// in temperature.
+type isotherm struct {
+	temperature bool
+	lowCount    image14bit.Intensity14
+	highCount   image14bit.Intensity14
+	lowTemp     physic.Temperature
+	highTemp    physic.Temperature
+	color       color.NRGBA
+}
+
+// isotherms implements flag.Value.
+type isotherms []isotherm
+
+func (i *isotherms) String() string {
+	out := make([]string, len(*i))
+	for n, s := range *i {
+		c := s.color
+		if s.temperature {
+			out[n] = fmt.Sprintf("%gC..%gC:%02x%02x%02x%02x", radiometry.Celsius(s.lowTemp), radiometry.Celsius(s.highTemp), c.R, c.G, c.B, c.A)
+		} else {
+			out[n] = fmt.Sprintf("%d..%d:%02x%02x%02x%02x", s.lowCount, s.highCount, c.R, c.G, c.B, c.A)
+		}
+	}
+	return strings.Join(out, ",")
+}
+
+// Set parses "LOW..HIGH:RRGGBB[AA]" and appends it.
+//
+// LOW and HIGH are either raw counts like "8200", or temperatures with a unit
+// like "45C" or "318.15K".
+func (i *isotherms) Set(s string) error {
+	colon := strings.LastIndexByte(s, ':')
+	dots := strings.Index(s, "..")
+	if colon == -1 || dots == -1 || dots > colon {
+		return fmt.Errorf("invalid isotherm %q; expected LOW..HIGH:RRGGBB", s)
+	}
+	var iso isotherm
+	lowT, low, err := parseLevel(s[:dots])
+	if err != nil {
+		return err
+	}
+	highT, high, err := parseLevel(s[dots+2 : colon])
+	if err != nil {
+		return err
+	}
+	if lowT != highT {
+		return fmt.Errorf("invalid isotherm %q; mixed units", s)
+	}
+	if low > high {
+		low, high = high, low
+	}
+	if iso.temperature = lowT; lowT {
+		iso.lowTemp = physic.Temperature(low)
+		iso.highTemp = physic.Temperature(high)
+	} else {
+		iso.lowCount = image14bit.Intensity14(low)
+		iso.highCount = image14bit.Intensity14(high)
+	}
+	c := s[colon+1:]
+	iso.color.A = 255
+	switch len(c) {
+	case 6:
+		_, err = fmt.Sscanf(c, "%02x%02x%02x", &iso.color.R, &iso.color.G, &iso.color.B)
+	case 8:
+		_, err = fmt.Sscanf(c, "%02x%02x%02x%02x", &iso.color.R, &iso.color.G, &iso.color.B, &iso.color.A)
+	default:
+		err = fmt.Errorf("invalid color %q", c)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid isotherm %q: %v", s, err)
+	}
+	*i = append(*i, iso)
+	return nil
+}
+
+// parseLevel parses a count or a temperature in C or K. The value is
+// returned in counts or in physic.Temperature units respectively.
+func parseLevel(s string) (bool, int64, error) {
+	unit := physic.Temperature(0)
+	offset := physic.Temperature(0)
+	if strings.HasSuffix(s, "C") {
+		unit, offset = physic.Kelvin, physic.ZeroCelsius
+	} else if strings.HasSuffix(s, "K") {
+		unit = physic.Kelvin
+	}
+	if unit == 0 {
+		v, err := strconv.ParseUint(s, 10, 16)
+		if err != nil {
+			return false, 0, fmt.Errorf("invalid count %q", s)
+		}
+		return false, int64(v), nil
+	}
+	v, err := strconv.ParseFloat(s[:len(s)-1], 64)
+	if err != nil {
+		return false, 0, fmt.Errorf("invalid temperature %q", s)
+	}
+	return true, int64(physic.Temperature(v*float64(unit)) + offset), nil
+}
@@ -88,7 +88,7 @@ func (s *Seeder) sendImgs(client *http.Client, imgs []*lepton.Frame) {
 		now := time.Now().UTC()
 		var w bytes.Buffer
 		for i, img := range imgs {
-			if err := png.Encode(&w, img); err != nil {
+			if err := frameio.WritePNG(&w, img, &frameio.Info{Time: now}); err != nil {
 				panic(err)
 			}
 			req.Items[i].Timestamp = now
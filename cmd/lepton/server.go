@@ -13,10 +13,14 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"image/png"
 	"log"
 	"net/http"
 	"sync"
 
+	"github.com/maruel/go-lepton/frameio"
+	"github.com/maruel/go-lepton/gray14"
+	"github.com/maruel/go-lepton/track"
 	"github.com/maruel/interrupt"
 	"github.com/maruel/serve-dir/loghttp"
 	"golang.org/x/net/websocket"
@@ -26,26 +30,68 @@ import (
 type WebServer struct {
 	cond      sync.Cond
 	state     string
-	images    [9 * 10]*lepton.Frame // 10 seconds worth of images. Each image is ~10kb.
-	lastIndex int                   // Index of the most recent image.
+	images    [9 * 10]capture // 10 seconds worth of images. Each image is ~10kb.
+	lastIndex int             // Index of the most recent image.
+	opts      *renderOpts
+	pipeline  *pipeline
+	// stats and isotherms are calculated once for each image for all the
+	// clients.
+	stats     [9 * 10]*gray14.Statistics
+	isotherms [9 * 10][]gray14.Isotherm
+	isoFailed bool // Converting the isotherms failed once, to not spam the logs.
 }
 
-func (s *WebServer) AddImg(img *lepton.Frame) {
+// frameInfo is the JSON header sent for each frame on the stream.
+type frameInfo struct {
+	lepton.Metadata
+	Stats *gray14.Statistics
+	// Isotherms are the default isotherms, converted to counts for this frame.
+	Isotherms []gray14.Isotherm
+	// Tracks are the objects followed, if tracking is enabled.
+	Tracks []track.Track
+	// Fallen are the IDs of the tracks of the people currently fallen.
+	Fallen []int `json:",omitempty"`
+}
+
+func (s *WebServer) AddImg(img capture) {
+	var stats *gray14.Statistics
+	var isos []gray14.Isotherm
+	if img.Frame != nil {
+		stats = gray14.Stats(img.Gray14, nil)
+		var err error
+		if isos, err = s.opts.resolveIsotherms(s.opts.isotherms, &img.Metadata); err != nil && !s.isoFailed {
+			// Not fatal, e.g. the telemetry is disabled.
+			log.Printf("isotherms: %v", err)
+			s.isoFailed = true
+		}
+	}
 	s.cond.L.Lock()
 	defer s.cond.L.Unlock()
 	s.lastIndex = (s.lastIndex + 1) % len(s.images)
 	s.images[s.lastIndex] = img
+	s.stats[s.lastIndex] = stats
+	s.isotherms[s.lastIndex] = isos
 	s.cond.Broadcast()
 }
 
-func StartWebServer(port int) *WebServer {
+func StartWebServer(port int, opts *renderOpts, p *pipeline) *WebServer {
 	w := &WebServer{
 		cond:      *sync.NewCond(&sync.Mutex{}),
 		lastIndex: -1,
+		opts:      opts,
+		pipeline:  p,
 	}
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", w.root)
 	mux.HandleFunc("/favicon.ico", w.favicon)
+	mux.HandleFunc("/palette", w.paletteHandler)
+	mux.HandleFunc("/palettes", w.palettesHandler)
+	mux.HandleFunc("/snapshot.png", w.snapshot)
+	mux.HandleFunc("/raw.png", w.raw)
+	mux.HandleFunc("/raw.tiff", w.raw)
+	mux.HandleFunc("/radiometric.jpg", w.radiometric)
+	mux.HandleFunc("/frame.csv", w.csv)
+	mux.HandleFunc("/counts", w.counts)
 	mux.Handle("/stream", websocket.Handler(w.stream))
 	fmt.Printf("Listening on %d\n", port)
 	go http.ListenAndServe(fmt.Sprintf(":%d", port), &loghttp.Handler{Handler: mux})
@@ -75,6 +121,168 @@ func (s *WebServer) favicon(w http.ResponseWriter, r *http.Request) {
 	w.Write(read("photo_ir.png"))
 }
 
+// palettesHandler returns the names of the registered palettes.
+func (s *WebServer) palettesHandler(w http.ResponseWriter, r *http.Request) {
+	data := struct {
+		Default string
+		Names   []string
+	}{s.opts.palette, gray14.PaletteNames()}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(&data)
+}
+
+// paletteHandler returns the colors of the palette specified with ?name=.
+func (s *WebServer) paletteHandler(w http.ResponseWriter, r *http.Request) {
+	p, err := s.opts.getPalette(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(p)
+}
+
+// snapshot returns the most recent image as a PNG.
+//
+// See render for the supported query parameters.
+func (s *WebServer) snapshot(w http.ResponseWriter, r *http.Request) {
+	img := s.lastImg()
+	if img.Frame == nil {
+		http.Error(w, "No image yet", http.StatusServiceUnavailable)
+		return
+	}
+	dst, err := s.opts.render(r, img.Frame, img.Time)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "no-cache")
+	png.Encode(w, dst)
+}
+
+// raw returns the most recent frame without loss as a 16 bits PNG or TIFF,
+// with its metadata.
+func (s *WebServer) raw(w http.ResponseWriter, r *http.Request) {
+	img := s.lastImg()
+	if img.Frame == nil {
+		http.Error(w, "No image yet", http.StatusServiceUnavailable)
+		return
+	}
+	info := &frameio.Info{Time: img.Time, Serial: s.pipeline.serial}
+	var buf bytes.Buffer
+	var err error
+	if r.URL.Path == "/raw.tiff" {
+		w.Header().Set("Content-Type", "image/tiff")
+		err = frameio.WriteTIFF(&buf, img.Frame, info)
+	} else {
+		w.Header().Set("Content-Type", "image/png")
+		err = frameio.WritePNG(&buf, img.Frame, info)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(buf.Bytes())
+}
+
+// radiometric returns the most recent frame as a FLIR radiometric JPEG.
+//
+// The preview is rendered like snapshot. See render for the supported query
+// parameters.
+func (s *WebServer) radiometric(w http.ResponseWriter, r *http.Request) {
+	img := s.lastImg()
+	if img.Frame == nil {
+		http.Error(w, "No image yet", http.StatusServiceUnavailable)
+		return
+	}
+	l, err := s.opts.calib.ForFrame(&img.Frame.Metadata)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	preview, err := s.opts.render(r, img.Frame, img.Time)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	out := &frameio.FLIR{
+		Frame:   img.Frame,
+		Info:    frameio.Info{Time: img.Time, Serial: s.pipeline.serial},
+		Camera:  frameio.NewFLIRCamera(l),
+		Preview: preview,
+	}
+	var buf bytes.Buffer
+	if err := frameio.WriteFLIR(&buf, out, 90); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(buf.Bytes())
+}
+
+// csv returns the most recent frame as a matrix of temperatures, or of raw
+// counts when the frame has no telemetry.
+//
+// ?units= selects C, K or counts, the default is specified with -units.
+func (s *WebServer) csv(w http.ResponseWriter, r *http.Request) {
+	img := s.lastImg()
+	if img.Frame == nil {
+		http.Error(w, "No image yet", http.StatusServiceUnavailable)
+		return
+	}
+	l, kelvin, err := s.opts.units(r, &img.Frame.Metadata)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var buf bytes.Buffer
+	if err := frameio.WriteCSV(&buf, img.Frame, &frameio.Info{Time: img.Time, Serial: s.pipeline.serial}, l, kelvin); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(buf.Bytes())
+}
+
+// counts returns the counting totals per line and zone on GET, and resets
+// them on POST.
+func (s *WebServer) counts(w http.ResponseWriter, r *http.Request) {
+	c := s.pipeline.counter
+	if c == nil {
+		http.Error(w, "Counting is disabled", http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case "GET":
+	case "POST":
+		c.Reset()
+		if err := s.pipeline.saveCounts(); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	json.NewEncoder(w).Encode(c.Totals())
+}
+
+// lastImg returns the most recent image, if any.
+func (s *WebServer) lastImg() capture {
+	s.cond.L.Lock()
+	defer s.cond.L.Unlock()
+	if s.lastIndex == -1 {
+		return capture{}
+	}
+	return s.images[s.lastIndex]
+}
+
 // stream sends all images as PseudoRGB as WebSocket frames.
 func (s *WebServer) stream(w *websocket.Conn) {
 	log.Printf("websocket %s", w.Config().Origin)
@@ -104,8 +312,9 @@ func (s *WebServer) stream(w *websocket.Conn) {
 
 			// Note: time.Duration and CentiC are sent as raw, which is less nice
 			// but easier to process.
-			if img != nil {
-				err = json.NewEncoder(&buf).Encode(&img.Metadata)
+			if img.Frame != nil {
+				info := frameInfo{Metadata: img.Metadata, Stats: s.stats[s.lastIndex], Isotherms: s.isotherms[s.lastIndex], Tracks: img.Tracks, Fallen: img.Fallen}
+				err = json.NewEncoder(&buf).Encode(&info)
 				if err == nil {
 					buf.Write([]byte("\n"))
 					encoder := base64.NewEncoder(base64.StdEncoding, &buf)
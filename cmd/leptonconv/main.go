@@ -0,0 +1,172 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// leptonconv converts saved Lepton frames to other file formats.
+//
+// The input files are the lossless PNG, TIFF and radiometric JPEG files
+// served by lepton, and the .lrec recordings it writes. They are processed in
+// the order specified.
+//
+// Usage:
+//
+//	leptonconv -o frames.npz raw*.png
+//	leptonconv -o frames.npz camera.lrec
+//	leptonconv -units K -o frame.csv raw.tiff
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/maruel/go-lepton/frameio"
+	"github.com/maruel/go-lepton/radiometry"
+	"github.com/maruel/go-lepton/recording"
+	"periph.io/x/periph/devices/lepton"
+)
+
+func mainImpl() error {
+	out := flag.String("o", "", "output file; .npy or .csv for a single frame, .npz for a sequence")
+	units := flag.String("units", "C", "units of the values written to .csv: C, K or counts")
+	tlinear := flag.Bool("tlinear", false, "the camera was configured with TLinear enabled, for radiometric measurements")
+	flag.Parse()
+
+	if *out == "" {
+		return errors.New("-o is required")
+	}
+	switch *units {
+	case "C", "K", "counts":
+	default:
+		return fmt.Errorf("invalid units %q; expected C, K or counts", *units)
+	}
+	if len(flag.Args()) == 0 {
+		return errors.New("specify at least one input file")
+	}
+	var frames []frame
+	for _, p := range flag.Args() {
+		f, err := readFrames(p)
+		if err != nil {
+			return fmt.Errorf("%s: %v", p, err)
+		}
+		frames = append(frames, f...)
+	}
+	calib := radiometry.Default
+	calib.TLinear = *tlinear
+	return writeFrames(*out, frames, &calib, *units)
+}
+
+func main() {
+	if err := mainImpl(); err != nil {
+		fmt.Fprintf(os.Stderr, "\nleptonconv: %s.\n", err)
+		os.Exit(1)
+	}
+}
+
+// Private details.
+
+// frame is a frame read from a file.
+type frame struct {
+	*lepton.Frame
+	info *frameio.Info
+}
+
+// readFrames reads the frames in the file at path p.
+func readFrames(p string) ([]frame, error) {
+	if strings.ToLower(filepath.Ext(p)) == ".lrec" {
+		return readRecording(p)
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var out frame
+	switch strings.ToLower(filepath.Ext(p)) {
+	case ".png":
+		out.Frame, out.info, err = frameio.ReadPNG(f)
+	case ".tif", ".tiff":
+		out.Frame, out.info, err = frameio.ReadTIFF(f)
+	case ".jpg", ".jpeg":
+		var img *frameio.FLIR
+		if img, err = frameio.ReadFLIR(f); err == nil {
+			out.Frame, out.info = img.Frame, &img.Info
+		}
+	default:
+		return nil, errors.New("unsupported input format")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []frame{out}, nil
+}
+
+// readRecording reads all the frames of a recording.
+func readRecording(p string) ([]frame, error) {
+	r, err := recording.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	out := make([]frame, 0, r.Len())
+	for {
+		f, t, err := r.Next()
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, frame{f, &frameio.Info{Time: t, Serial: r.Serial()}})
+	}
+}
+
+// writeFrames writes frames to the file at path p.
+//
+// calib and units are used for the .csv format.
+func writeFrames(p string, frames []frame, calib *radiometry.Calibration, units string) (err error) {
+	ext := strings.ToLower(filepath.Ext(p))
+	switch ext {
+	case ".npy", ".csv":
+		if len(frames) != 1 {
+			return fmt.Errorf("%s holds a single frame, got %d; use .npz", ext, len(frames))
+		}
+	case ".npz":
+	default:
+		return errors.New("unsupported output format")
+	}
+	f, err := os.Create(p)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err2 := f.Close(); err == nil {
+			err = err2
+		}
+	}()
+	switch ext {
+	case ".npy":
+		return frameio.WriteNPY(f, frames[0].Frame)
+	case ".csv":
+		fr := frames[0]
+		var l *radiometry.Linear
+		if units != "counts" {
+			// Raw counts are written when the frame has no telemetry.
+			if v, err := calib.ForFrame(&fr.Metadata); err == nil {
+				l = &v
+			}
+		}
+		return frameio.WriteCSV(f, fr.Frame, fr.info, l, units == "K")
+	}
+	z := frameio.NewNPZWriter(f)
+	for _, fr := range frames {
+		if err := z.Add(fr.Frame, fr.info); err != nil {
+			return err
+		}
+	}
+	return z.Close()
+}
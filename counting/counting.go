@@ -0,0 +1,322 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package counting counts the tracked objects crossing virtual lines or
+// entering and leaving zones.
+//
+// The totals are meant to be persisted so they survive restarts.
+package counting
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/maruel/go-lepton/blob"
+	"github.com/maruel/go-lepton/gray14"
+	"github.com/maruel/go-lepton/track"
+)
+
+// Direction is the direction of a crossing.
+type Direction string
+
+// Directions.
+const (
+	// In is crossing a line from its left side to its right side, looking
+	// from A to B, or entering a zone.
+	In Direction = "in"
+	// Out is the opposite of In.
+	Out Direction = "out"
+)
+
+// Line is a virtual line.
+//
+// In image coordinates, with the Y axis going down, an object moving down
+// across a line going from left to right is going In.
+type Line struct {
+	Name string
+	A    image.Point
+	B    image.Point
+}
+
+// Config is the lines and zones to count on.
+type Config struct {
+	Lines []Line
+	// Zones count the objects entering and leaving a region. An object first
+	// seen inside a zone is not counted as entering it.
+	Zones []gray14.Region
+}
+
+// LoadConfig loads a JSON encoded Config.
+func LoadConfig(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Config{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	names := map[string]bool{}
+	for _, l := range c.Lines {
+		if names[l.Name] {
+			return nil, fmt.Errorf("%s: duplicate name %q", path, l.Name)
+		}
+		names[l.Name] = true
+	}
+	for _, z := range c.Zones {
+		if names[z.Name] {
+			return nil, fmt.Errorf("%s: duplicate name %q", path, z.Name)
+		}
+		names[z.Name] = true
+	}
+	return c, nil
+}
+
+// Total is the running count of a line or a zone.
+type Total struct {
+	In  int
+	Out int
+	// Inside is the number of objects currently in a zone. It is not
+	// persisted.
+	Inside int `json:",omitempty"`
+}
+
+// Event is an object crossing a line or entering or leaving a zone.
+type Event struct {
+	// Name is the name of the line or the zone.
+	Name      string
+	TrackID   int
+	Direction Direction
+	// Time is the time since the camera started.
+	Time time.Duration
+}
+
+// Path returns the path of the totals for the camera serial in dir.
+func Path(dir string, serial uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("counts-%x.json", serial))
+}
+
+// Counter counts crossings.
+//
+// It is safe for concurrent use.
+type Counter struct {
+	config Config
+
+	mu     sync.Mutex
+	totals map[string]*Total
+	states map[int]*state // Per track ID.
+}
+
+// New returns a Counter with all the totals at zero.
+func New(c *Config) *Counter {
+	ctr := &Counter{config: *c, states: map[int]*state{}}
+	ctr.reset()
+	return ctr
+}
+
+// Update processes the tracks of a frame, as returned by track.Tracker, and
+// returns the crossings.
+//
+// Tracks not in the list are forgotten.
+func (c *Counter) Update(tracks []track.Track) []Event {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var events []Event
+	seen := make(map[int]bool, len(tracks))
+	for i := range tracks {
+		t := &tracks[i]
+		seen[t.ID] = true
+		pos := t.Pos()
+		s := c.states[t.ID]
+		if s == nil {
+			s = &state{sides: make([]float64, len(c.config.Lines)), lastPos: make([]blob.Point, len(c.config.Lines)), inside: make([]bool, len(c.config.Zones))}
+			for n := range c.config.Lines {
+				s.sides[n] = side(&c.config.Lines[n], pos)
+				s.lastPos[n] = pos
+			}
+			for n, z := range c.config.Zones {
+				if s.inside[n] = contains(z.ROI, pos); s.inside[n] {
+					c.totals[z.Name].Inside++
+				}
+			}
+			c.states[t.ID] = s
+			continue
+		}
+		for n := range c.config.Lines {
+			l := &c.config.Lines[n]
+			sd := side(l, pos)
+			if sd == 0 {
+				// On the line; wait to see on which side it ends up.
+				continue
+			}
+			if s.sides[n] != 0 && (sd > 0) != (s.sides[n] > 0) && crosses(l, s.lastPos[n], pos) {
+				d := In
+				if sd < 0 {
+					d = Out
+				}
+				events = append(events, c.add(l.Name, t.ID, d, t.LastSeen))
+			}
+			s.sides[n] = sd
+			s.lastPos[n] = pos
+		}
+		for n, z := range c.config.Zones {
+			in := contains(z.ROI, pos)
+			if in == s.inside[n] {
+				continue
+			}
+			s.inside[n] = in
+			if in {
+				c.totals[z.Name].Inside++
+				events = append(events, c.add(z.Name, t.ID, In, t.LastSeen))
+			} else {
+				c.totals[z.Name].Inside--
+				events = append(events, c.add(z.Name, t.ID, Out, t.LastSeen))
+			}
+		}
+	}
+	for id, s := range c.states {
+		if !seen[id] {
+			for n, z := range c.config.Zones {
+				if s.inside[n] {
+					c.totals[z.Name].Inside--
+				}
+			}
+			delete(c.states, id)
+		}
+	}
+	return events
+}
+
+// Totals returns a copy of the totals per line or zone name.
+func (c *Counter) Totals() map[string]Total {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make(map[string]Total, len(c.totals))
+	for k, v := range c.totals {
+		out[k] = *v
+	}
+	return out
+}
+
+// Reset sets all the totals back to zero.
+func (c *Counter) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reset()
+	// Recount the objects currently inside the zones.
+	for _, s := range c.states {
+		for n, z := range c.config.Zones {
+			if s.inside[n] {
+				c.totals[z.Name].Inside++
+			}
+		}
+	}
+}
+
+// Load loads the totals saved with Save. Totals of lines or zones not in the
+// configuration are ignored.
+func (c *Counter) Load(path string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var totals map[string]Total
+	if err := json.Unmarshal(b, &totals); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, v := range totals {
+		if t := c.totals[k]; t != nil {
+			t.In = v.In
+			t.Out = v.Out
+		}
+	}
+	return nil
+}
+
+// Save saves the totals as a JSON file.
+//
+// The file is replaced atomically so a power loss doesn't corrupt it.
+func (c *Counter) Save(path string) error {
+	// Hold the lock while writing so concurrent saves are serialized.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	totals := make(map[string]Total, len(c.totals))
+	for k, v := range c.totals {
+		totals[k] = Total{In: v.In, Out: v.Out}
+	}
+	b, err := json.MarshalIndent(totals, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, append(b, '\n'), 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// Private details.
+
+// state is the state of a track relative to the lines and zones.
+type state struct {
+	sides   []float64    // Last non-zero side of each line.
+	lastPos []blob.Point // Position when sides was last updated.
+	inside  []bool       // Per zone.
+}
+
+func (c *Counter) reset() {
+	c.totals = map[string]*Total{}
+	for _, l := range c.config.Lines {
+		c.totals[l.Name] = &Total{}
+	}
+	for _, z := range c.config.Zones {
+		c.totals[z.Name] = &Total{}
+	}
+}
+
+// add counts a crossing. c.mu must be held.
+func (c *Counter) add(name string, id int, d Direction, t time.Duration) Event {
+	if d == In {
+		c.totals[name].In++
+	} else {
+		c.totals[name].Out++
+	}
+	return Event{Name: name, TrackID: id, Direction: d, Time: t}
+}
+
+// side returns a positive value if p is on the right side of l, negative if
+// on the left side and 0 if on the line.
+func side(l *Line, p blob.Point) float64 {
+	return float64(l.B.X-l.A.X)*(p.Y-float64(l.A.Y)) - float64(l.B.Y-l.A.Y)*(p.X-float64(l.A.X))
+}
+
+// crosses returns true if the segment from p to q, which are on each side of
+// the infinite line l, crosses it between A and B.
+func crosses(l *Line, p, q blob.Point) bool {
+	// Both ends of the line must be on opposite sides of the segment, or on
+	// it.
+	s := func(x, y float64) float64 {
+		return (q.X-p.X)*(y-p.Y) - (q.Y-p.Y)*(x-p.X)
+	}
+	a := s(float64(l.A.X), float64(l.A.Y))
+	b := s(float64(l.B.X), float64(l.B.Y))
+	return (a <= 0 && b >= 0) || (a >= 0 && b <= 0)
+}
+
+// contains returns true if the pixel containing p is in r.
+func contains(r gray14.ROI, p blob.Point) bool {
+	return r.Contains(image.Pt(int(p.X), int(p.Y)))
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package counting
+
+import (
+	"image"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maruel/go-lepton/blob"
+	"github.com/maruel/go-lepton/gray14"
+	"github.com/maruel/go-lepton/track"
+)
+
+func TestCounter(t *testing.T) {
+	c := New(&Config{
+		Lines: []Line{{Name: "door", A: image.Pt(0, 30), B: image.Pt(60, 30)}},
+		Zones: []gray14.Region{{Name: "desk", ROI: gray14.Rect(image.Rect(60, 0, 80, 20))}},
+	})
+	// Track 1 goes down through the door, lands exactly on the line on the
+	// way. Track 2 goes up right of the door, which doesn't count.
+	steps := [][]track.Track{
+		{tr(1, 30, 20), tr(2, 70, 40)},
+		{tr(1, 30, 30), tr(2, 70, 35)},
+		{tr(1, 30, 40), tr(2, 70, 25)},
+		{tr(1, 30, 40), tr(2, 70, 15)},
+	}
+	var events []Event
+	for _, s := range steps {
+		events = append(events, c.Update(s)...)
+	}
+	if len(events) != 2 {
+		t.Fatal(events)
+	}
+	if e := events[0]; e.Name != "door" || e.TrackID != 1 || e.Direction != In {
+		t.Fatal(e)
+	}
+	if e := events[1]; e.Name != "desk" || e.TrackID != 2 || e.Direction != In {
+		t.Fatal(e)
+	}
+	if tot := c.Totals(); tot["door"] != (Total{In: 1}) || tot["desk"] != (Total{In: 1, Inside: 1}) {
+		t.Fatal(tot)
+	}
+
+	// Track 1 comes back up, track 2 disappears while at the desk.
+	c.Update([]track.Track{tr(1, 30, 25)})
+	if tot := c.Totals(); tot["door"] != (Total{In: 1, Out: 1}) || tot["desk"] != (Total{In: 1}) {
+		t.Fatal(tot)
+	}
+
+	// The totals are persisted, but not the objects inside.
+	d, err := ioutil.TempDir("", "counting")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(d)
+	p := filepath.Join(d, "counts.json")
+	if err := c.Save(p); err != nil {
+		t.Fatal(err)
+	}
+	c.Reset()
+	if tot := c.Totals(); tot["door"] != (Total{}) {
+		t.Fatal(tot)
+	}
+	if err := c.Load(p); err != nil {
+		t.Fatal(err)
+	}
+	if tot := c.Totals(); tot["door"] != (Total{In: 1, Out: 1}) || tot["desk"] != (Total{In: 1}) {
+		t.Fatal(tot)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	d, err := ioutil.TempDir("", "counting")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(d)
+	p := filepath.Join(d, "counting.json")
+	data := `{"Lines": [{"Name": "a", "A": {"X": 0, "Y": 30}, "B": {"X": 80, "Y": 30}}],
+		"Zones": [{"Name": "b", "Type": "rect", "Shape": {"Min": {"X": 0, "Y": 0}, "Max": {"X": 10, "Y": 10}}}]}`
+	if err := ioutil.WriteFile(p, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadConfig(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Lines) != 1 || c.Lines[0].B != image.Pt(80, 30) || len(c.Zones) != 1 || !c.Zones[0].ROI.Contains(image.Pt(5, 5)) {
+		t.Fatal(c)
+	}
+	data = `{"Lines": [{"Name": "a"}], "Zones": [{"Name": "a", "Type": "rect", "Shape": {}}]}`
+	if err := ioutil.WriteFile(p, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(p); err == nil {
+		t.Fatal("expected duplicate name error")
+	}
+}
+
+func tr(id int, x, y float64) track.Track {
+	return track.Track{ID: id, Trajectory: []track.Sample{{Pos: blob.Point{X: x, Y: y}}}}
+}
@@ -0,0 +1,183 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package fall detects people falling and staying down.
+//
+// It works on the tracks of the people in the scene, as returned by
+// track.Tracker. A person is considered down when their bounding box is
+// wider than tall and low in the frame. An alert is raised when a person
+// previously seen upright has been down and still for long enough.
+//
+// The camera is expected to be mounted on a wall, looking across the room.
+package fall
+
+import (
+	"image"
+	"sort"
+	"time"
+
+	"github.com/maruel/go-lepton/blob"
+	"github.com/maruel/go-lepton/track"
+	"periph.io/x/periph/devices/lepton"
+)
+
+// Opts are the thresholds of Detector.
+type Opts struct {
+	// UprightAspect is the width/height ratio of the bounding box at or
+	// below which a person is upright.
+	UprightAspect float64
+	// LyingAspect is the width/height ratio of the bounding box at or above
+	// which a person is lying.
+	LyingAspect float64
+	// FloorLevel is the position of the centroid, as a fraction of the frame
+	// height from the top, at or below which a lying person is on the floor.
+	// 0 disables this check.
+	FloorLevel float64
+	// StillSpeed is the speed in pixels per second below which a person is
+	// still.
+	StillSpeed float64
+	// Duration is how long a person must be down and still to raise an
+	// alert.
+	Duration time.Duration
+}
+
+// DefaultOpts are conservative defaults.
+var DefaultOpts = Opts{
+	UprightAspect: 0.8,
+	LyingAspect:   1.3,
+	FloorLevel:    0.5,
+	StillSpeed:    3,
+	Duration:      10 * time.Second,
+}
+
+// Kind is the kind of an Event.
+type Kind string
+
+// Kinds of events.
+const (
+	// Fallen is raised when a person has been down for Opts.Duration.
+	Fallen Kind = "fallen"
+	// Recovered is raised when a fallen person is upright again.
+	Recovered Kind = "recovered"
+)
+
+// Event is a change in the state of a person.
+type Event struct {
+	Kind    Kind
+	TrackID int
+	// Time is the time since the camera started.
+	Time time.Duration
+	Pos  blob.Point
+	// Since is when the person went down.
+	Since time.Duration
+}
+
+// Detector raises alerts when people fall.
+//
+// It is not safe for concurrent use.
+type Detector struct {
+	opts   Opts
+	states map[int]*state
+}
+
+// New returns a Detector. If opts is nil, DefaultOpts is used.
+func New(opts *Opts) *Detector {
+	d := &Detector{opts: DefaultOpts, states: map[int]*state{}}
+	if opts != nil {
+		d.opts = *opts
+	}
+	return d
+}
+
+// Update processes the tracks of frame f and returns the changes.
+//
+// Tracks not in the list are forgotten.
+func (d *Detector) Update(f *lepton.Frame, tracks []track.Track) []Event {
+	now := f.Metadata.SinceStartup
+	height := f.Bounds().Dy()
+	var events []Event
+	seen := make(map[int]bool, len(tracks))
+	for i := range tracks {
+		t := &tracks[i]
+		seen[t.ID] = true
+		s := d.states[t.ID]
+		if s == nil {
+			s = &state{}
+			d.states[t.ID] = s
+		}
+		if t.Missed != 0 {
+			// Occluded, keep the current state.
+			continue
+		}
+		a := aspect(t.Blob.Bounds)
+		switch {
+		case a <= d.opts.UprightAspect:
+			s.upright = true
+			s.down = false
+			if s.fallen {
+				s.fallen = false
+				events = append(events, Event{Kind: Recovered, TrackID: t.ID, Time: now, Pos: t.Pos(), Since: s.since})
+			}
+		case a >= d.opts.LyingAspect && d.onFloor(t.Pos(), height) && t.Speed() < d.opts.StillSpeed:
+			if !s.upright {
+				// Never seen standing, e.g. someone already lying in bed.
+				break
+			}
+			if !s.down {
+				s.down = true
+				s.since = now
+			}
+			if !s.fallen && now-s.since >= d.opts.Duration {
+				s.fallen = true
+				events = append(events, Event{Kind: Fallen, TrackID: t.ID, Time: now, Pos: t.Pos(), Since: s.since})
+			}
+		default:
+			// In between, e.g. bending over or moving on the floor. Moving
+			// restarts the timer.
+			if !s.fallen {
+				s.down = false
+			}
+		}
+	}
+	for id := range d.states {
+		if !seen[id] {
+			delete(d.states, id)
+		}
+	}
+	return events
+}
+
+// Fallen returns the people currently fallen, sorted by track ID.
+func (d *Detector) Fallen() []int {
+	var out []int
+	for id, s := range d.states {
+		if s.fallen {
+			out = append(out, id)
+		}
+	}
+	sort.Ints(out)
+	return out
+}
+
+// Private details.
+
+// state is the state of a person.
+type state struct {
+	upright bool          // Has been seen upright.
+	down    bool          // Currently down and still.
+	since   time.Duration // When down became true.
+	fallen  bool          // An alert was raised.
+}
+
+func (d *Detector) onFloor(p blob.Point, height int) bool {
+	return d.opts.FloorLevel == 0 || p.Y >= d.opts.FloorLevel*float64(height)
+}
+
+// aspect returns the width/height ratio of r.
+func aspect(r image.Rectangle) float64 {
+	if r.Dy() == 0 {
+		return 0
+	}
+	return float64(r.Dx()) / float64(r.Dy())
+}
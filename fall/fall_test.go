@@ -0,0 +1,148 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package fall
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"github.com/maruel/go-lepton/blob"
+	"github.com/maruel/go-lepton/leptontest"
+	"github.com/maruel/go-lepton/track"
+)
+
+func TestFall(t *testing.T) {
+	r := newRoom()
+	// Walk in, fall, stay down then get up.
+	events := r.walk(20)
+	events = append(events, r.fall()...)
+	events = append(events, r.stay(12*time.Second)...)
+	if len(events) != 1 {
+		t.Fatal(events)
+	}
+	e := events[0]
+	if e.Kind != Fallen || e.TrackID != 1 || e.Time-e.Since < 10*time.Second || e.Time-e.Since > 11*time.Second {
+		t.Fatal(e)
+	}
+	if e.Pos.Y < 40 {
+		t.Fatal(e.Pos)
+	}
+	if f := r.d.Fallen(); len(f) != 1 || f[0] != 1 {
+		t.Fatal(f)
+	}
+	events = r.getUp()
+	if len(events) != 1 || events[0].Kind != Recovered || events[0].TrackID != 1 {
+		t.Fatal(events)
+	}
+	if f := r.d.Fallen(); len(f) != 0 {
+		t.Fatal(f)
+	}
+}
+
+func TestFall_GetUpQuickly(t *testing.T) {
+	r := newRoom()
+	events := r.walk(20)
+	events = append(events, r.fall()...)
+	events = append(events, r.stay(5*time.Second)...)
+	events = append(events, r.getUp()...)
+	events = append(events, r.walk(20)...)
+	if len(events) != 0 {
+		t.Fatal(events)
+	}
+}
+
+func TestFall_LyingFromTheStart(t *testing.T) {
+	r := newRoom()
+	r.s.Objects = []leptontest.Object{{Bounds: image.Rect(20, 40, 50, 48), Intensity: 9000}}
+	if events := r.stay(20 * time.Second); len(events) != 0 {
+		t.Fatal(events)
+	}
+}
+
+func TestFall_Thresholds(t *testing.T) {
+	r := newRoom()
+	// Lying down high in the frame, like on a top bunk, is ignored.
+	r.d = New(&Opts{UprightAspect: 0.8, LyingAspect: 1.3, FloorLevel: 0.9, StillSpeed: 3, Duration: time.Second})
+	r.walk(20)
+	r.fall()
+	if events := r.stay(5 * time.Second); len(events) != 0 {
+		t.Fatal(events)
+	}
+	// Without the floor check, it triggers after a second.
+	r.d.opts.FloorLevel = 0
+	events := r.stay(2 * time.Second)
+	if len(events) != 1 || events[0].Time-events[0].Since < time.Second || events[0].Time-events[0].Since > 1200*time.Millisecond {
+		t.Fatal(events)
+	}
+}
+
+// room is a synthetic scene with a person, and the processing from the
+// frames to the fall events.
+type room struct {
+	s       *leptontest.Scene
+	tracker *track.Tracker
+	d       *Detector
+	upright image.Rectangle // Bounds of the person before falling.
+}
+
+func newRoom() *room {
+	r := &room{s: leptontest.NewScene(), tracker: track.New(nil), d: New(nil)}
+	r.s.Objects = []leptontest.Object{{Bounds: image.Rect(10, 15, 16, 45), Intensity: 9000}}
+	return r
+}
+
+// step renders a frame and processes it.
+func (r *room) step() []Event {
+	f := r.s.Next()
+	o := blob.DefaultOpts
+	o.Threshold = 8600
+	active, _ := r.tracker.Update(&f.Metadata, blob.Detect(f.Gray14, &o))
+	return r.d.Update(f, active)
+}
+
+// walk moves the person right by one pixel per frame.
+func (r *room) walk(frames int) []Event {
+	var events []Event
+	for i := 0; i < frames; i++ {
+		r.s.Objects[0].Bounds = r.s.Objects[0].Bounds.Add(image.Pt(1, 0))
+		events = append(events, r.step()...)
+	}
+	return events
+}
+
+// fall morphs the upright person into a lying one over a second.
+func (r *room) fall() []Event {
+	b := r.s.Objects[0].Bounds
+	r.upright = b
+	return r.morph(image.Rectangle{b.Min.Add(image.Pt(-12, 25)), b.Max.Add(image.Pt(12, 3))})
+}
+
+// getUp morphs the lying person back into an upright one over a second.
+func (r *room) getUp() []Event {
+	return r.morph(r.upright)
+}
+
+// morph moves the bounds of the person progressively to b.
+func (r *room) morph(b image.Rectangle) []Event {
+	var events []Event
+	from := r.s.Objects[0].Bounds
+	for i := 1; i <= 9; i++ {
+		r.s.Objects[0].Bounds = image.Rect(
+			from.Min.X+(b.Min.X-from.Min.X)*i/9, from.Min.Y+(b.Min.Y-from.Min.Y)*i/9,
+			from.Max.X+(b.Max.X-from.Max.X)*i/9, from.Max.Y+(b.Max.Y-from.Max.Y)*i/9)
+		events = append(events, r.step()...)
+	}
+	return events
+}
+
+// stay keeps the scene still for d.
+func (r *room) stay(d time.Duration) []Event {
+	var events []Event
+	for i := 0; i < int(d/(time.Second/9)); i++ {
+		events = append(events, r.step()...)
+	}
+	return events
+}
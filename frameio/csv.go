@@ -0,0 +1,70 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package frameio
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/maruel/go-lepton/radiometry"
+	"periph.io/x/periph/devices/lepton"
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// WriteCSV writes the frame as a matrix of values, one row per line, for
+// use in a spreadsheet.
+//
+// The values are temperatures converted with l, in Kelvin if kelvin is true,
+// otherwise in Celsius. When l is nil, the raw counts are written instead.
+//
+// The matrix is preceded by a header with the capture time, the camera serial
+// and the units, then an empty line. info may be nil.
+func WriteCSV(w io.Writer, f *lepton.Frame, info *Info, l *radiometry.Linear, kelvin bool) error {
+	if info == nil {
+		info = &Info{}
+	}
+	c := csv.NewWriter(w)
+	t := ""
+	if !info.Time.IsZero() {
+		t = info.Time.Format(time.RFC3339Nano)
+	}
+	units := "counts"
+	if l != nil {
+		if units = "C"; kelvin {
+			units = "K"
+		}
+	}
+	hdr := [][]string{
+		{"Time", t},
+		{"Serial", strconv.FormatUint(info.Serial, 16)},
+		{"Units", units},
+		{},
+	}
+	if err := c.WriteAll(hdr); err != nil {
+		return err
+	}
+	b := f.Bounds()
+	row := make([]string, b.Dx())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		base := f.PixOffset(b.Min.X, y)
+		for x, v := range f.Pix[base : base+b.Dx()] {
+			switch {
+			case l == nil:
+				row[x] = strconv.Itoa(int(v))
+			case kelvin:
+				row[x] = strconv.FormatFloat(radiometry.Kelvin(l.Temperature(image14bit.Intensity14(v))), 'f', 2, 64)
+			default:
+				row[x] = strconv.FormatFloat(radiometry.Celsius(l.Temperature(image14bit.Intensity14(v))), 'f', 2, 64)
+			}
+		}
+		if err := c.Write(row); err != nil {
+			return err
+		}
+	}
+	c.Flush()
+	return c.Error()
+}
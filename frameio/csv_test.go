@@ -0,0 +1,52 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package frameio
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/maruel/go-lepton/radiometry"
+	"periph.io/x/periph/conn/physic"
+)
+
+func TestWriteCSV(t *testing.T) {
+	f, info := testFrame()
+	f.Pix[1] = 15000
+	l := &radiometry.Linear{Gain: 10 * physic.MilliKelvin}
+	data := []struct {
+		l      *radiometry.Linear
+		kelvin bool
+		units  string
+		value  string
+	}{
+		{nil, false, "counts", "15000"},
+		{l, false, "C", "-123.15"},
+		{l, true, "K", "150.00"},
+	}
+	for _, line := range data {
+		var buf bytes.Buffer
+		if err := WriteCSV(&buf, f, info, line.l, line.kelvin); err != nil {
+			t.Fatal(err)
+		}
+		want := "Time,2026-10-16T12:34:56.000000789Z\nSerial,1234abcd\nUnits," + line.units + "\n\n"
+		if !strings.HasPrefix(buf.String(), want) {
+			t.Fatalf("%q", buf.String()[:len(want)])
+		}
+		r := csv.NewReader(strings.NewReader(buf.String()[len(want):]))
+		rows, err := r.ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rows) != 60 || len(rows[0]) != 80 {
+			t.Fatal(len(rows), len(rows[0]))
+		}
+		if rows[0][1] != line.value {
+			t.Fatal(rows[0][1])
+		}
+	}
+}
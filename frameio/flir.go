@@ -0,0 +1,510 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package frameio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maruel/go-lepton/gray14"
+	"github.com/maruel/go-lepton/radiometry"
+	"periph.io/x/periph/conn/physic"
+	"periph.io/x/periph/devices/lepton"
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// FLIR is a radiometric JPEG, as saved by FLIR cameras and understood by FLIR
+// Tools, exiftool and similar software.
+//
+// The file is a regular JPEG with the raw counts and the calibration stored
+// in FLIR APP1 segments.
+type FLIR struct {
+	Frame  *lepton.Frame
+	Info   Info
+	Camera FLIRCamera
+	// Preview is the visible image. When nil, WriteFLIR renders the frame
+	// with AGCLinear and the default palette.
+	Preview image.Image
+}
+
+// FLIRCamera is the calibration stored in the FLIR CameraInfo record.
+//
+// A raw count S is converted to a temperature T in kelvin with
+//
+//	T = B / ln(R1 / (R2 * (S + O)) + F)
+//
+// after compensating for the emissivity and the reflected temperature.
+type FLIRCamera struct {
+	Emissivity       float64
+	ObjectDistance   float64 // In meters.
+	ReflectedTemp    physic.Temperature
+	AtmosphericTemp  physic.Temperature
+	RelativeHumidity float64 // Between 0 and 1.
+	PlanckR1         float64
+	PlanckB          float64
+	PlanckF          float64
+	PlanckO          int32
+	PlanckR2         float64
+	// RangeMin and RangeMax are the temperatures the camera can measure.
+	RangeMin physic.Temperature
+	RangeMax physic.Temperature
+	Model    string
+	Serial   string
+	Time     time.Time
+}
+
+// NewFLIRCamera returns the FLIR calibration matching the transfer function
+// l.
+//
+// Lepton counts are linear with the temperature, unlike the radiance based
+// counts of FLIR cameras, so the Planck constants are chosen to make the FLIR
+// formula linear. Temperature then matches l within half a count. The range
+// covers all the counts of l. The emissivity is 1 and the reflected and
+// atmospheric temperatures are 20°C.
+func NewFLIRCamera(l radiometry.Linear) FLIRCamera {
+	gain := radiometry.Kelvin(l.Gain)
+	// With a small B, 1/(exp(B/T)-1) is T/B - 1/2 for practical purposes.
+	r1 := planckB / gain
+	return FLIRCamera{
+		Emissivity:       1,
+		ObjectDistance:   1,
+		ReflectedTemp:    physic.ZeroCelsius + 20*physic.Kelvin,
+		AtmosphericTemp:  physic.ZeroCelsius + 20*physic.Kelvin,
+		RelativeHumidity: 0.5,
+		PlanckR1:         r1,
+		PlanckB:          planckB,
+		PlanckF:          1,
+		PlanckO:          int32(math.Round(radiometry.Kelvin(l.Offset)/gain - r1/2)),
+		PlanckR2:         1,
+		RangeMin:         l.Temperature(0),
+		RangeMax:         l.Temperature(l.MaxCount()),
+		Model:            "Lepton",
+	}
+}
+
+// Temperature returns the object temperature for count v.
+//
+// The atmospheric transmission is not taken into account, which is fine for
+// the short distances the Lepton is used at.
+func (c *FLIRCamera) Temperature(v image14bit.Intensity14) physic.Temperature {
+	raw := float64(v)
+	if c.Emissivity > 0 && c.Emissivity < 1 {
+		refl := c.PlanckR1/(c.PlanckR2*(math.Exp(c.PlanckB/radiometry.Kelvin(c.ReflectedTemp))-c.PlanckF)) - float64(c.PlanckO)
+		raw = (raw - (1-c.Emissivity)*refl) / c.Emissivity
+	}
+	k := c.PlanckB / math.Log(c.PlanckR1/(c.PlanckR2*(raw+float64(c.PlanckO)))+c.PlanckF)
+	return physic.Temperature(math.Round(k * float64(physic.Kelvin)))
+}
+
+// WriteFLIR writes a radiometric JPEG.
+//
+// quality is the JPEG quality of the preview. The frame metadata, as returned
+// by Fields, is stored in a JPEG comment.
+func WriteFLIR(w io.Writer, img *FLIR, quality int) error {
+	f := img.Frame
+	preview := img.Preview
+	if preview == nil {
+		preview = gray14.DefaultPalette.Colorize(gray14.AGCLinear(f.Gray14))
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, preview, &jpeg.Options{Quality: quality}); err != nil {
+		return err
+	}
+	c := img.Camera
+	if c.Time.IsZero() {
+		c.Time = img.Info.Time
+	}
+	if c.Serial == "" && img.Info.Serial != 0 {
+		c.Serial = strconv.FormatUint(img.Info.Serial, 16)
+	}
+	fff := encodeFFF(f.Gray14, &c)
+
+	// Insert the segments right after SOI.
+	b := buf.Bytes()
+	if _, err := w.Write(b[:2]); err != nil {
+		return err
+	}
+	n := (len(fff) + flirChunk - 1) / flirChunk
+	if n > 256 {
+		return errors.New("frameio: frame too large for a FLIR JPEG")
+	}
+	for i := 0; i < n; i++ {
+		end := (i + 1) * flirChunk
+		if end > len(fff) {
+			end = len(fff)
+		}
+		data := append([]byte{'F', 'L', 'I', 'R', 0, 1, byte(i), byte(n - 1)}, fff[i*flirChunk:end]...)
+		if err := writeSegment(w, markerAPP1, data); err != nil {
+			return err
+		}
+	}
+	var com strings.Builder
+	info := img.Info
+	for _, field := range Fields(&f.Metadata, &info) {
+		com.WriteString(field.Key + "=" + field.Value + "\n")
+	}
+	if err := writeSegment(w, markerCOM, []byte(com.String())); err != nil {
+		return err
+	}
+	_, err := w.Write(b[2:])
+	return err
+}
+
+// ReadFLIR reads a radiometric JPEG.
+//
+// Files written by FLIR cameras are accepted. The frame metadata is only
+// present in files written by WriteFLIR.
+func ReadFLIR(r io.Reader) (*FLIR, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < 2 || b[0] != 0xFF || b[1] != 0xD8 {
+		return nil, errors.New("frameio: not a JPEG")
+	}
+	chunks := map[int][]byte{}
+	last := -1
+	var fields []Field
+	for i := 2; ; {
+		if i+4 > len(b) || b[i] != 0xFF {
+			return nil, errors.New("frameio: invalid JPEG")
+		}
+		marker := b[i+1]
+		if marker == 0xFF {
+			// Fill byte.
+			i++
+			continue
+		}
+		if marker == markerSOS || marker == markerEOI {
+			break
+		}
+		n := int(binary.BigEndian.Uint16(b[i+2:]))
+		if n < 2 || i+2+n > len(b) {
+			return nil, errors.New("frameio: JPEG truncated")
+		}
+		data := b[i+4 : i+2+n]
+		switch marker {
+		case markerAPP1:
+			if len(data) >= 8 && string(data[:5]) == "FLIR\x00" {
+				chunks[int(data[6])] = data[8:]
+				last = int(data[7])
+			}
+		case markerCOM:
+			for _, l := range strings.Split(string(data), "\n") {
+				if k := strings.IndexByte(l, '='); k != -1 {
+					fields = append(fields, Field{l[:k], l[k+1:]})
+				}
+			}
+		}
+		i += 2 + n
+	}
+	if last == -1 {
+		return nil, errors.New("frameio: not a radiometric JPEG")
+	}
+	var fff []byte
+	for i := 0; i <= last; i++ {
+		c, ok := chunks[i]
+		if !ok {
+			return nil, fmt.Errorf("frameio: missing FLIR segment %d", i)
+		}
+		fff = append(fff, c...)
+	}
+	out := &FLIR{}
+	g, err := decodeFFF(fff, &out.Camera)
+	if err != nil {
+		return nil, err
+	}
+	m, info, err := ParseFields(fields)
+	if err != nil {
+		return nil, err
+	}
+	if info.Time.IsZero() {
+		info.Time = out.Camera.Time
+	}
+	if info.Serial == 0 && out.Camera.Serial != "" {
+		// WriteFLIR writes the serial in hexadecimal.
+		info.Serial, _ = strconv.ParseUint(out.Camera.Serial, 16, 64)
+	}
+	out.Frame = &lepton.Frame{Gray14: g, Metadata: m}
+	out.Info = info
+	if out.Preview, err = jpeg.Decode(bytes.NewReader(b)); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Private details.
+
+// planckB is the Planck B constant used by NewFLIRCamera.
+const planckB = 1
+
+// JPEG markers.
+const (
+	markerAPP1 = 0xE1
+	markerCOM  = 0xFE
+	markerSOS  = 0xDA
+	markerEOI  = 0xD9
+)
+
+// flirChunk is the maximum amount of FFF data in an APP1 segment.
+const flirChunk = 0xFFFF - 2 - 8
+
+// FFF records.
+const (
+	recRawData    = 0x01
+	recCameraInfo = 0x20
+	// cameraInfoSize is large enough for all the fields written.
+	cameraInfoSize = 0x400
+)
+
+// writeSegment writes a JPEG segment.
+func writeSegment(w io.Writer, marker byte, data []byte) error {
+	if len(data) > 0xFFFF-2 {
+		return errors.New("frameio: JPEG segment too large")
+	}
+	hdr := []byte{0xFF, marker, byte((len(data) + 2) >> 8), byte(len(data) + 2)}
+	if _, err := w.Write(hdr); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+// encodeFFF returns the FLIR file format blob with the raw data and the
+// camera info.
+//
+// Like the cameras do, the header is big endian and the records are little
+// endian.
+func encodeFFF(g *image14bit.Gray14, c *FLIRCamera) []byte {
+	b := g.Bounds()
+	raw := make([]byte, 0x20+2*b.Dx()*b.Dy())
+	le := binary.LittleEndian
+	le.PutUint16(raw, 2)
+	le.PutUint16(raw[2:], uint16(b.Dx()))
+	le.PutUint16(raw[4:], uint16(b.Dy()))
+	min, max := uint16(0xFFFF), uint16(0)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		base := g.PixOffset(b.Min.X, y)
+		for x, v := range g.Pix[base : base+b.Dx()] {
+			le.PutUint16(raw[0x20+2*((y-b.Min.Y)*b.Dx()+x):], v)
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+		}
+	}
+
+	info := make([]byte, cameraInfoSize)
+	le.PutUint16(info, 2)
+	putFloat := func(off int, v float64) {
+		le.PutUint32(info[off:], math.Float32bits(float32(v)))
+	}
+	putFloat(0x20, c.Emissivity)
+	putFloat(0x24, c.ObjectDistance)
+	putFloat(0x28, radiometry.Kelvin(c.ReflectedTemp))
+	putFloat(0x2C, radiometry.Kelvin(c.AtmosphericTemp))
+	putFloat(0x30, radiometry.Kelvin(c.AtmosphericTemp))
+	putFloat(0x34, 1)
+	putFloat(0x3C, c.RelativeHumidity)
+	putFloat(0x58, c.PlanckR1)
+	putFloat(0x5C, c.PlanckB)
+	putFloat(0x60, c.PlanckF)
+	// Typical atmospheric transmission constants.
+	putFloat(0x70, 0.006569)
+	putFloat(0x74, 0.01262)
+	putFloat(0x78, -0.002276)
+	putFloat(0x7C, -0.00667)
+	putFloat(0x80, 1.9)
+	putFloat(0x90, radiometry.Kelvin(c.RangeMax))
+	putFloat(0x94, radiometry.Kelvin(c.RangeMin))
+	copy(info[0xD4:0xD4+32], c.Model)
+	copy(info[0x104:0x104+16], c.Serial)
+	copy(info[0x114:0x114+16], "go-lepton")
+	le.PutUint32(info[0x308:], uint32(c.PlanckO))
+	putFloat(0x30C, c.PlanckR2)
+	le.PutUint16(info[0x310:], min)
+	le.PutUint16(info[0x312:], max)
+	le.PutUint16(info[0x33C:], max-min)
+	if !c.Time.IsZero() {
+		_, offset := c.Time.Zone()
+		le.PutUint32(info[0x384:], uint32(c.Time.Unix()))
+		le.PutUint32(info[0x388:], uint32(c.Time.Nanosecond()/1000000))
+		le.PutUint16(info[0x38C:], uint16(int16(-offset/60)))
+	}
+
+	records := []struct {
+		typ  uint16
+		data []byte
+	}{{recRawData, raw}, {recCameraInfo, info}}
+	be := binary.BigEndian
+	out := make([]byte, 0x40+0x20*len(records))
+	copy(out, "FFF\x00")
+	copy(out[4:0x14], "go-lepton")
+	be.PutUint32(out[0x14:], 100)
+	be.PutUint32(out[0x18:], 0x40)
+	be.PutUint32(out[0x1C:], uint32(len(records)))
+	be.PutUint32(out[0x20:], uint32(len(records)+1))
+	for i, r := range records {
+		e := out[0x40+0x20*i:]
+		be.PutUint16(e, r.typ)
+		be.PutUint32(e[4:], 100)
+		be.PutUint32(e[8:], uint32(i+1))
+		be.PutUint32(e[0xC:], uint32(len(out)))
+		be.PutUint32(e[0x10:], uint32(len(r.data)))
+		out = append(out, r.data...)
+	}
+	return out
+}
+
+// decodeFFF decodes the raw data and the camera info from a FLIR file format
+// blob.
+func decodeFFF(b []byte, c *FLIRCamera) (*image14bit.Gray14, error) {
+	if len(b) < 0x40 || string(b[:4]) != "FFF\x00" {
+		return nil, errors.New("frameio: invalid FLIR data")
+	}
+	var order binary.ByteOrder = binary.BigEndian
+	if v := order.Uint32(b[0x14:]); v < 100 || v >= 200 {
+		order = binary.LittleEndian
+	}
+	dir := uint64(order.Uint32(b[0x18:]))
+	n := uint64(order.Uint32(b[0x1C:]))
+	if dir+0x20*n > uint64(len(b)) {
+		return nil, errors.New("frameio: FLIR data truncated")
+	}
+	var g *image14bit.Gray14
+	hasInfo := false
+	for i := uint64(0); i < n; i++ {
+		e := b[dir+0x20*i:]
+		off := uint64(order.Uint32(e[0xC:]))
+		size := uint64(order.Uint32(e[0x10:]))
+		if off+size > uint64(len(b)) {
+			return nil, errors.New("frameio: FLIR data truncated")
+		}
+		data := b[off : off+size]
+		var err error
+		switch order.Uint16(e) {
+		case recRawData:
+			g, err = decodeRawData(data)
+		case recCameraInfo:
+			err = decodeCameraInfo(data, c)
+			hasInfo = true
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if g == nil || !hasInfo {
+		return nil, errors.New("frameio: FLIR data without raw data or camera info")
+	}
+	return g, nil
+}
+
+func decodeRawData(data []byte) (*image14bit.Gray14, error) {
+	if len(data) < 0x20 {
+		return nil, errors.New("frameio: FLIR raw data truncated")
+	}
+	order := recordOrder(data)
+	w := int(order.Uint16(data[2:]))
+	h := int(order.Uint16(data[4:]))
+	// The size is checked before decoding the PNG, since a small blob can
+	// expand to any size.
+	if w == 0 || h == 0 || w > maxSize || h > maxSize {
+		return nil, errors.New("frameio: unsupported FLIR raw data size")
+	}
+	r := image.Rect(0, 0, w, h)
+	data = data[0x20:]
+	if bytes.HasPrefix(data, []byte(pngSignature)) {
+		// The cameras store the pixels byte swapped in the PNG.
+		if c, err := png.DecodeConfig(bytes.NewReader(data)); err != nil || c.Width != w || c.Height != h {
+			return nil, errors.New("frameio: invalid FLIR raw PNG")
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		p, ok := img.(*image.Gray16)
+		if !ok || p.Bounds() != r {
+			return nil, errors.New("frameio: invalid FLIR raw PNG")
+		}
+		data = make([]byte, 2*w*h)
+		for y := 0; y < h; y++ {
+			copy(data[2*w*y:], p.Pix[p.PixOffset(0, y):p.PixOffset(w, y)])
+		}
+		order = binary.LittleEndian
+	}
+	if len(data) < 2*w*h {
+		return nil, errors.New("frameio: FLIR raw data truncated")
+	}
+	g := image14bit.NewGray14(r)
+	for i := range g.Pix {
+		g.Pix[i] = order.Uint16(data[2*i:])
+	}
+	return g, nil
+}
+
+func decodeCameraInfo(data []byte, c *FLIRCamera) error {
+	if len(data) < 0x390 {
+		return errors.New("frameio: FLIR camera info truncated")
+	}
+	order := recordOrder(data)
+	float := func(off int) float64 {
+		// Round to the precision of a float32 to not show artifacts.
+		v := float64(math.Float32frombits(order.Uint32(data[off:])))
+		f, _ := strconv.ParseFloat(strconv.FormatFloat(v, 'g', 7, 64), 64)
+		return f
+	}
+	temp := func(off int) physic.Temperature {
+		return physic.Temperature(math.Round(float(off) * float64(physic.Kelvin)))
+	}
+	str := func(off, size int) string {
+		s := data[off : off+size]
+		if i := bytes.IndexByte(s, 0); i != -1 {
+			s = s[:i]
+		}
+		return string(s)
+	}
+	c.Emissivity = float(0x20)
+	c.ObjectDistance = float(0x24)
+	c.ReflectedTemp = temp(0x28)
+	c.AtmosphericTemp = temp(0x2C)
+	c.RelativeHumidity = float(0x3C)
+	c.PlanckR1 = float(0x58)
+	c.PlanckB = float(0x5C)
+	c.PlanckF = float(0x60)
+	c.RangeMax = temp(0x90)
+	c.RangeMin = temp(0x94)
+	c.Model = str(0xD4, 32)
+	c.Serial = str(0x104, 16)
+	c.PlanckO = int32(order.Uint32(data[0x308:]))
+	c.PlanckR2 = float(0x30C)
+	if t := order.Uint32(data[0x384:]); t != 0 {
+		ms := order.Uint32(data[0x388:]) & 0xFFFF
+		tz := int(int16(order.Uint16(data[0x38C:])))
+		c.Time = time.Unix(int64(t), int64(ms)*int64(time.Millisecond)).In(time.FixedZone("", -tz*60))
+	}
+	return nil
+}
+
+// recordOrder returns the byte order of a FFF record, which starts with the
+// value 2.
+func recordOrder(data []byte) binary.ByteOrder {
+	if binary.LittleEndian.Uint16(data) == 2 {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}
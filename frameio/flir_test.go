@@ -0,0 +1,154 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package frameio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/maruel/go-lepton/radiometry"
+	"periph.io/x/periph/conn/physic"
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+func TestFLIR(t *testing.T) {
+	f, info := testFrame()
+	l := radiometry.Linear{Gain: 10 * physic.MilliKelvin}
+	img := &FLIR{Frame: f, Info: *info, Camera: NewFLIRCamera(l)}
+	var buf bytes.Buffer
+	if err := WriteFLIR(&buf, img, 90); err != nil {
+		t.Fatal(err)
+	}
+	// Must be readable by the standard library.
+	if _, err := jpeg.Decode(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadFLIR(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equalFrames(t, f, got.Frame)
+	if !got.Info.Time.Equal(info.Time) || got.Info.Serial != info.Serial {
+		t.Fatalf("%+v", got.Info)
+	}
+	if got.Preview.Bounds() != f.Bounds() {
+		t.Fatal(got.Preview.Bounds())
+	}
+	c := got.Camera
+	if c.Emissivity != 1 || c.Model != "Lepton" || c.Serial != "1234abcd" || c.PlanckO != img.Camera.PlanckO {
+		t.Fatalf("%+v", c)
+	}
+	if !c.Time.Equal(info.Time.Truncate(1e6)) {
+		t.Fatal(c.Time)
+	}
+}
+
+func TestFLIRCamera(t *testing.T) {
+	data := []radiometry.Linear{
+		// TLinear, high gain.
+		{Gain: 10 * physic.MilliKelvin, Max: 0xFFFF},
+		// TLinear, low gain.
+		{Gain: 100 * physic.MilliKelvin, Max: 0xFFFF},
+		// No TLinear, the offset is not a multiple of the gain.
+		{Offset: physic.ZeroCelsius + 25*physic.Kelvin - 8192*21700*physic.MicroKelvin, Gain: 21700 * physic.MicroKelvin},
+	}
+	for i, l := range data {
+		c := NewFLIRCamera(l)
+		if c.RangeMax != l.Temperature(l.MaxCount()) {
+			t.Fatalf("#%d: %s", i, c.RangeMax)
+		}
+		for _, v := range []image14bit.Intensity14{1000, 8192, 16000, 29315} {
+			if v > l.MaxCount() {
+				continue
+			}
+			if l.Offset+physic.Temperature(v)*l.Gain < physic.ZeroCelsius-40*physic.Kelvin {
+				continue
+			}
+			want := l.Temperature(v)
+			d := c.Temperature(v) - want
+			if d < 0 {
+				d = -d
+			}
+			if d > l.Gain/2+physic.MilliKelvin {
+				t.Fatalf("#%d: %d: %s != %s", i, v, c.Temperature(v), want)
+			}
+		}
+	}
+}
+
+func TestFLIRCamera_emissivity(t *testing.T) {
+	c := NewFLIRCamera(radiometry.Linear{Gain: 10 * physic.MilliKelvin})
+	c.Emissivity = 0.5
+	// The object is hotter than it appears, the reflection is colder.
+	v := image14bit.Intensity14((physic.ZeroCelsius + 40*physic.Kelvin) / (10 * physic.MilliKelvin))
+	got := c.Temperature(v)
+	want := physic.ZeroCelsius + 60*physic.Kelvin
+	if d := got - want; d < -physic.Kelvin || d > physic.Kelvin {
+		t.Fatal(got)
+	}
+}
+
+func TestReadFLIR_err(t *testing.T) {
+	var buf bytes.Buffer
+	f, _ := testFrame()
+	if err := jpeg.Encode(&buf, f.Gray14, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadFLIR(&buf); err == nil {
+		t.Fatal("regular JPEG must be refused")
+	}
+	if _, err := ReadFLIR(bytes.NewReader([]byte("\x89PNG"))); err == nil {
+		t.Fatal("expected error")
+	}
+	// The size is checked before allocating the image.
+	raw := make([]byte, 0x20+4)
+	raw[0] = 2
+	raw[2], raw[3] = 0xFF, 0x3F
+	raw[4], raw[5] = 0xFF, 0x3F
+	if _, err := decodeRawData(raw); err == nil {
+		t.Fatal("truncated raw data must be refused")
+	}
+	// A tiny PNG that claims to be huge.
+	var p bytes.Buffer
+	if err := png.Encode(&p, image.NewGray16(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	b := p.Bytes()
+	binary.BigEndian.PutUint32(b[16:], 1<<14)
+	binary.BigEndian.PutUint32(b[20:], 1<<14)
+	binary.BigEndian.PutUint32(b[29:], crc32.ChecksumIEEE(b[12:29]))
+	raw = append(raw[:0x20], b...)
+	raw[2], raw[3] = 0x00, 0x40
+	raw[4], raw[5] = 0x00, 0x40
+	if _, err := decodeRawData(raw); err == nil {
+		t.Fatal("huge raw PNG must be refused")
+	}
+}
+
+func TestFLIR_tlinear(t *testing.T) {
+	// TLinear counts use the whole 16 bits; 29315 is 20°C.
+	f, info := testFrame()
+	f.Pix[0] = 29315
+	f.Pix[1] = 0xFFFF
+	l := radiometry.Linear{Gain: 10 * physic.MilliKelvin, Max: 0xFFFF}
+	img := &FLIR{Frame: f, Info: *info, Camera: NewFLIRCamera(l)}
+	var buf bytes.Buffer
+	if err := WriteFLIR(&buf, img, 90); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadFLIR(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equalFrames(t, f, got.Frame)
+	if v := radiometry.Kelvin(got.Camera.RangeMax); v < 655.3 || v > 655.4 {
+		t.Fatal(v)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package frameio reads and writes frames in file formats usable by other
+// tools.
+//
+// All the formats keep the raw intensity so no data is lost, including the 16
+// bits counts of TLinear mode. The frame metadata is kept along as text fields
+// when the format allows it.
+package frameio
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"periph.io/x/periph/conn/physic"
+	"periph.io/x/periph/devices/lepton"
+	"periph.io/x/periph/devices/lepton/cci"
+)
+
+// Info is the information about a frame that is not part of
+// lepton.Metadata.
+type Info struct {
+	// Time is the wall clock time at which the frame was captured.
+	Time time.Time
+	// Serial is the serial number of the camera, as returned by GetSerial().
+	Serial uint64
+}
+
+// Field is a named text value.
+type Field struct {
+	Key   string
+	Value string
+}
+
+// Fields returns the metadata and info as text fields, in a stable order.
+//
+// Temperatures are in Kelvin and durations use the time.Duration format.
+// info may be nil.
+func Fields(m *lepton.Metadata, info *Info) []Field {
+	var out []Field
+	if info != nil {
+		if !info.Time.IsZero() {
+			out = append(out, Field{"Time", info.Time.Format(time.RFC3339Nano)})
+		}
+		out = append(out, Field{"Serial", strconv.FormatUint(info.Serial, 16)})
+	}
+	return append(out,
+		Field{"SinceStartup", m.SinceStartup.String()},
+		Field{"FrameCount", strconv.FormatUint(uint64(m.FrameCount), 10)},
+		Field{"AvgValue", strconv.FormatUint(uint64(m.AvgValue), 10)},
+		Field{"Temp", formatTemp(m.Temp)},
+		Field{"TempHousing", formatTemp(m.TempHousing)},
+		Field{"RawTemp", strconv.FormatUint(uint64(m.RawTemp), 10)},
+		Field{"RawTempHousing", strconv.FormatUint(uint64(m.RawTempHousing), 10)},
+		Field{"FFCSince", m.FFCSince.String()},
+		Field{"FFCTemp", formatTemp(m.FFCTemp)},
+		Field{"FFCTempHousing", formatTemp(m.FFCTempHousing)},
+		Field{"FFCState", strconv.FormatUint(uint64(m.FFCState), 10)},
+		Field{"FFCDesired", strconv.FormatBool(m.FFCDesired)},
+		Field{"Overtemp", strconv.FormatBool(m.Overtemp)},
+	)
+}
+
+// ParseFields is the reverse of Fields.
+//
+// Unknown keys are ignored so files written by other tools can be read.
+func ParseFields(fields []Field) (lepton.Metadata, Info, error) {
+	var m lepton.Metadata
+	var info Info
+	for _, f := range fields {
+		var err error
+		switch f.Key {
+		case "Time":
+			info.Time, err = time.Parse(time.RFC3339Nano, f.Value)
+		case "Serial":
+			info.Serial, err = strconv.ParseUint(f.Value, 16, 64)
+		case "SinceStartup":
+			m.SinceStartup, err = time.ParseDuration(f.Value)
+		case "FrameCount":
+			var v uint64
+			v, err = strconv.ParseUint(f.Value, 10, 32)
+			m.FrameCount = uint32(v)
+		case "AvgValue":
+			var v uint64
+			v, err = strconv.ParseUint(f.Value, 10, 16)
+			m.AvgValue = uint16(v)
+		case "Temp":
+			m.Temp, err = parseTemp(f.Value)
+		case "TempHousing":
+			m.TempHousing, err = parseTemp(f.Value)
+		case "RawTemp":
+			var v uint64
+			v, err = strconv.ParseUint(f.Value, 10, 16)
+			m.RawTemp = uint16(v)
+		case "RawTempHousing":
+			var v uint64
+			v, err = strconv.ParseUint(f.Value, 10, 16)
+			m.RawTempHousing = uint16(v)
+		case "FFCSince":
+			m.FFCSince, err = time.ParseDuration(f.Value)
+		case "FFCTemp":
+			m.FFCTemp, err = parseTemp(f.Value)
+		case "FFCTempHousing":
+			m.FFCTempHousing, err = parseTemp(f.Value)
+		case "FFCState":
+			var v uint64
+			v, err = strconv.ParseUint(f.Value, 10, 8)
+			m.FFCState = cci.FFCState(v)
+		case "FFCDesired":
+			m.FFCDesired, err = strconv.ParseBool(f.Value)
+		case "Overtemp":
+			m.Overtemp, err = strconv.ParseBool(f.Value)
+		}
+		if err != nil {
+			return m, info, fmt.Errorf("frameio: invalid %s: %v", f.Key, err)
+		}
+	}
+	return m, info, nil
+}
+
+// Private details.
+
+// maxSize is the largest width or height accepted when reading a frame. It is
+// well beyond any thermal sensor and keeps a small compressed file from
+// allocating gigabytes.
+const maxSize = 2048
+
+// formatTemp formats t in Kelvin without losing precision.
+func formatTemp(t physic.Temperature) string {
+	return strconv.FormatFloat(float64(t)/float64(physic.Kelvin), 'f', -1, 64) + "K"
+}
+
+func parseTemp(s string) (physic.Temperature, error) {
+	if !strings.HasSuffix(s, "K") {
+		return 0, errors.New("expected Kelvin")
+	}
+	v, err := strconv.ParseFloat(s[:len(s)-1], 64)
+	if err != nil {
+		return 0, err
+	}
+	return physic.Temperature(math.Round(v * float64(physic.Kelvin))), nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package frameio
+
+import (
+	"image"
+	"reflect"
+	"testing"
+	"time"
+
+	"periph.io/x/periph/conn/physic"
+	"periph.io/x/periph/devices/lepton"
+	"periph.io/x/periph/devices/lepton/cci"
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+func TestFields(t *testing.T) {
+	f, info := testFrame()
+	m, got, err := ParseFields(Fields(&f.Metadata, info))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m != f.Metadata {
+		t.Fatalf("%+v != %+v", m, f.Metadata)
+	}
+	if !got.Time.Equal(info.Time) || got.Serial != info.Serial {
+		t.Fatalf("%+v != %+v", got, info)
+	}
+}
+
+func TestParseFields_err(t *testing.T) {
+	if _, _, err := ParseFields([]Field{{"Temp", "30C"}}); err == nil {
+		t.Fatal("expected error")
+	}
+	// Unknown keys are ignored.
+	if _, _, err := ParseFields([]Field{{"Foo", "bar"}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Private details.
+
+func testFrame() (*lepton.Frame, *Info) {
+	f := &lepton.Frame{
+		Gray14: image14bit.NewGray14(image.Rect(0, 0, 80, 60)),
+		Metadata: lepton.Metadata{
+			SinceStartup:   123456 * time.Millisecond,
+			FrameCount:     1111,
+			AvgValue:       8000,
+			Temp:           physic.ZeroCelsius + 31250*physic.MilliKelvin,
+			TempHousing:    physic.ZeroCelsius + 28100*physic.MilliKelvin,
+			RawTemp:        30000,
+			RawTempHousing: 29000,
+			FFCSince:       120 * time.Second,
+			FFCTemp:        physic.ZeroCelsius + 30*physic.Kelvin,
+			FFCTempHousing: physic.ZeroCelsius + 27*physic.Kelvin,
+			FFCState:       cci.FFCComplete,
+			FFCDesired:     true,
+		},
+	}
+	for i := range f.Pix {
+		f.Pix[i] = uint16(i*7) & (1<<14 - 1)
+	}
+	info := &Info{Time: time.Date(2026, 10, 16, 12, 34, 56, 789, time.UTC), Serial: 0x1234abcd}
+	return f, info
+}
+
+func equalFrames(t *testing.T, a, b *lepton.Frame) {
+	if a.Bounds() != b.Bounds() {
+		t.Fatalf("%v != %v", a.Bounds(), b.Bounds())
+	}
+	if !reflect.DeepEqual(a.Pix, b.Pix) {
+		t.Fatal("pixels differ")
+	}
+	if a.Metadata != b.Metadata {
+		t.Fatalf("%+v != %+v", a.Metadata, b.Metadata)
+	}
+}
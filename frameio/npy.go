@@ -0,0 +1,239 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package frameio
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"strings"
+
+	"periph.io/x/periph/conn/physic"
+	"periph.io/x/periph/devices/lepton"
+)
+
+// WriteNPY writes the frame as a NumPy .npy file.
+//
+// The array is uint16 with the shape (height, width), e.g. (60, 80). Load it
+// with numpy.load(). The metadata is not saved, use NPZWriter for that.
+func WriteNPY(w io.Writer, f *lepton.Frame) error {
+	b := f.Bounds()
+	return writeNPY(w, "<u2", []int{b.Dy(), b.Dx()}, framePixels(f))
+}
+
+// NPZWriter writes a sequence of frames as a NumPy .npz archive.
+//
+// Load it with numpy.load(). The archive contains the array "frames" as
+// uint16 with the shape (n, height, width) and one array of n values for
+// each metadata field:
+//
+//   - time: capture time in nanoseconds since the Unix epoch as int64, 0 if
+//     unknown
+//   - serial: uint64
+//   - since_startup, ffc_since: nanoseconds as int64
+//   - frame_count: uint32
+//   - avg_value, raw_temp, raw_temp_housing: uint16
+//   - temp, temp_housing, ffc_temp, ffc_temp_housing: kelvin as float64
+//   - ffc_state: uint8
+//   - ffc_desired, overtemp: bool
+//
+// The frames are kept in memory until Close, about 9.4KiB per frame.
+type NPZWriter struct {
+	w      io.Writer
+	bounds image.Rectangle
+	n      int
+	arrays []npyArray
+	closed bool
+}
+
+// NewNPZWriter returns a writer that writes the archive to w on Close.
+func NewNPZWriter(w io.Writer) *NPZWriter {
+	z := &NPZWriter{w: w, arrays: []npyArray{{name: "frames", descr: "<u2"}}}
+	for _, a := range npzArrays {
+		z.arrays = append(z.arrays, npyArray{name: a.name, descr: a.descr})
+	}
+	return z
+}
+
+// Add appends a frame. All frames must have the same size. info may be nil.
+func (z *NPZWriter) Add(f *lepton.Frame, info *Info) error {
+	if z.closed {
+		return errors.New("frameio: NPZWriter is closed")
+	}
+	if z.n == 0 {
+		z.bounds = f.Bounds()
+	} else if f.Bounds().Size() != z.bounds.Size() {
+		return fmt.Errorf("frameio: frame size %s doesn't match %s", f.Bounds().Size(), z.bounds.Size())
+	}
+	if info == nil {
+		info = &Info{}
+	}
+	z.arrays[0].data = append(z.arrays[0].data, framePixels(f)...)
+	for i, a := range npzArrays {
+		z.arrays[i+1].data = a.add(z.arrays[i+1].data, &f.Metadata, info)
+	}
+	z.n++
+	return nil
+}
+
+// Close writes the archive. It doesn't close the underlying writer.
+func (z *NPZWriter) Close() error {
+	if z.closed {
+		return errors.New("frameio: NPZWriter is closed")
+	}
+	z.closed = true
+	zw := zip.NewWriter(z.w)
+	for i, a := range z.arrays {
+		shape := []int{z.n}
+		if i == 0 {
+			shape = append(shape, z.bounds.Dy(), z.bounds.Dx())
+		}
+		w, err := zw.Create(a.name + ".npy")
+		if err != nil {
+			return err
+		}
+		if err := writeNPY(w, a.descr, shape, a.data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// Private details.
+
+// npyArray is an array being accumulated by NPZWriter.
+type npyArray struct {
+	name  string
+	descr string
+	data  []byte
+}
+
+// npzArrays are the metadata arrays, in the order they are saved after the
+// frames.
+var npzArrays = []struct {
+	name  string
+	descr string
+	add   func(b []byte, m *lepton.Metadata, info *Info) []byte
+}{
+	{"time", "<i8", func(b []byte, m *lepton.Metadata, info *Info) []byte {
+		var v int64
+		if !info.Time.IsZero() {
+			v = info.Time.UnixNano()
+		}
+		return appendUint64(b, uint64(v))
+	}},
+	{"serial", "<u8", func(b []byte, m *lepton.Metadata, info *Info) []byte {
+		return appendUint64(b, info.Serial)
+	}},
+	{"since_startup", "<i8", func(b []byte, m *lepton.Metadata, info *Info) []byte {
+		return appendUint64(b, uint64(m.SinceStartup))
+	}},
+	{"frame_count", "<u4", func(b []byte, m *lepton.Metadata, info *Info) []byte {
+		return appendUint32(b, m.FrameCount)
+	}},
+	{"avg_value", "<u2", func(b []byte, m *lepton.Metadata, info *Info) []byte {
+		return appendUint16(b, m.AvgValue)
+	}},
+	{"temp", "<f8", func(b []byte, m *lepton.Metadata, info *Info) []byte {
+		return appendKelvin(b, m.Temp)
+	}},
+	{"temp_housing", "<f8", func(b []byte, m *lepton.Metadata, info *Info) []byte {
+		return appendKelvin(b, m.TempHousing)
+	}},
+	{"raw_temp", "<u2", func(b []byte, m *lepton.Metadata, info *Info) []byte {
+		return appendUint16(b, m.RawTemp)
+	}},
+	{"raw_temp_housing", "<u2", func(b []byte, m *lepton.Metadata, info *Info) []byte {
+		return appendUint16(b, m.RawTempHousing)
+	}},
+	{"ffc_since", "<i8", func(b []byte, m *lepton.Metadata, info *Info) []byte {
+		return appendUint64(b, uint64(m.FFCSince))
+	}},
+	{"ffc_temp", "<f8", func(b []byte, m *lepton.Metadata, info *Info) []byte {
+		return appendKelvin(b, m.FFCTemp)
+	}},
+	{"ffc_temp_housing", "<f8", func(b []byte, m *lepton.Metadata, info *Info) []byte {
+		return appendKelvin(b, m.FFCTempHousing)
+	}},
+	{"ffc_state", "|u1", func(b []byte, m *lepton.Metadata, info *Info) []byte {
+		return append(b, byte(m.FFCState))
+	}},
+	{"ffc_desired", "|b1", func(b []byte, m *lepton.Metadata, info *Info) []byte {
+		return appendBool(b, m.FFCDesired)
+	}},
+	{"overtemp", "|b1", func(b []byte, m *lepton.Metadata, info *Info) []byte {
+		return appendBool(b, m.Overtemp)
+	}},
+}
+
+// writeNPY writes a version 1.0 .npy file. data must already be encoded.
+func writeNPY(w io.Writer, descr string, shape []int, data []byte) error {
+	dims := make([]string, len(shape))
+	for i, s := range shape {
+		dims[i] = fmt.Sprint(s)
+	}
+	s := strings.Join(dims, ", ")
+	if len(shape) == 1 {
+		s += ","
+	}
+	hdr := fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': (%s), }", descr, s)
+	// The header is padded with spaces and a newline so the data is aligned on
+	// 64 bytes.
+	const prefix = 10
+	pad := 64 - (prefix+len(hdr)+1)%64
+	if pad == 64 {
+		pad = 0
+	}
+	hdr += strings.Repeat(" ", pad) + "\n"
+	out := make([]byte, prefix, prefix+len(hdr))
+	copy(out, "\x93NUMPY\x01\x00")
+	binary.LittleEndian.PutUint16(out[8:], uint16(len(hdr)))
+	out = append(out, hdr...)
+	if _, err := w.Write(out); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+// framePixels returns the pixels as little endian uint16.
+func framePixels(f *lepton.Frame) []byte {
+	b := f.Bounds()
+	out := make([]byte, 0, 2*b.Dx()*b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		base := f.PixOffset(b.Min.X, y)
+		for _, v := range f.Pix[base : base+b.Dx()] {
+			out = appendUint16(out, v)
+		}
+	}
+	return out
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v), byte(v>>8))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	return appendUint32(appendUint32(b, uint32(v)), uint32(v>>32))
+}
+
+func appendKelvin(b []byte, t physic.Temperature) []byte {
+	return appendUint64(b, math.Float64bits(float64(t)/float64(physic.Kelvin)))
+}
+
+func appendBool(b []byte, v bool) []byte {
+	if v {
+		return append(b, 1)
+	}
+	return append(b, 0)
+}
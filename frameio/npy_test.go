@@ -0,0 +1,115 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package frameio
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"testing"
+)
+
+func TestWriteNPY(t *testing.T) {
+	f, _ := testFrame()
+	var buf bytes.Buffer
+	if err := WriteNPY(&buf, f); err != nil {
+		t.Fatal(err)
+	}
+	hdr, data := parseNPY(t, buf.Bytes())
+	if hdr != "{'descr': '<u2', 'fortran_order': False, 'shape': (60, 80), }" {
+		t.Fatal(hdr)
+	}
+	if len(data) != 2*80*60 || binary.LittleEndian.Uint16(data[2*81:]) != f.Pix[81] {
+		t.Fatal("invalid data")
+	}
+}
+
+func TestNPZWriter(t *testing.T) {
+	f, info := testFrame()
+	var buf bytes.Buffer
+	z := NewNPZWriter(&buf)
+	for i := 0; i < 3; i++ {
+		f.Metadata.FrameCount = uint32(100 + i)
+		if err := z.Add(f, info); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := z.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := z.Add(f, info); err == nil {
+		t.Fatal("expected error")
+	}
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	arrays := map[string][]byte{}
+	for _, file := range r.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		hdr, data := parseNPY(t, b)
+		arrays[file.Name] = data
+		switch file.Name {
+		case "frames.npy":
+			if hdr != "{'descr': '<u2', 'fortran_order': False, 'shape': (3, 60, 80), }" {
+				t.Fatal(hdr)
+			}
+		case "frame_count.npy":
+			if hdr != "{'descr': '<u4', 'fortran_order': False, 'shape': (3,), }" {
+				t.Fatal(hdr)
+			}
+		}
+	}
+	if len(arrays) != 1+len(npzArrays) {
+		t.Fatal(len(arrays))
+	}
+	if len(arrays["frames.npy"]) != 3*2*80*60 {
+		t.Fatal("invalid frames")
+	}
+	if c := arrays["frame_count.npy"]; binary.LittleEndian.Uint32(c[8:]) != 102 {
+		t.Fatal(c)
+	}
+	if s := arrays["serial.npy"]; binary.LittleEndian.Uint64(s) != info.Serial {
+		t.Fatal(s)
+	}
+	if d := arrays["ffc_desired.npy"]; !bytes.Equal(d, []byte{1, 1, 1}) {
+		t.Fatal(d)
+	}
+}
+
+func TestNPZWriter_size(t *testing.T) {
+	f, _ := testFrame()
+	z := NewNPZWriter(ioutil.Discard)
+	if err := z.Add(f, nil); err != nil {
+		t.Fatal(err)
+	}
+	f.Gray14.Rect.Max.X--
+	if err := z.Add(f, nil); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+// Private details.
+
+// parseNPY returns the header and the data of a .npy file.
+func parseNPY(t *testing.T, b []byte) (string, []byte) {
+	if len(b) < 10 || string(b[:8]) != "\x93NUMPY\x01\x00" {
+		t.Fatalf("invalid magic %q", b)
+	}
+	n := 10 + int(binary.LittleEndian.Uint16(b[8:]))
+	if n%64 != 0 || n > len(b) || b[n-1] != '\n' {
+		t.Fatalf("invalid header %q", b[:n])
+	}
+	return string(bytes.TrimRight(b[10:n], " \n")), b[n:]
+}
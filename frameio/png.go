@@ -0,0 +1,143 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package frameio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"io"
+	"io/ioutil"
+
+	"periph.io/x/periph/devices/lepton"
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// WritePNG writes the frame as a 16 bits grayscale PNG.
+//
+// The pixels are the raw intensities, so the image looks very dark in a
+// regular viewer. The metadata is stored in tEXt chunks, as returned by
+// Fields. info may be nil.
+func WritePNG(w io.Writer, f *lepton.Frame, info *Info) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, toGray16(f.Gray14)); err != nil {
+		return err
+	}
+	// Insert the text chunks right after IHDR, which is always first.
+	b := buf.Bytes()
+	const ihdrEnd = len(pngSignature) + 8 + 13 + 4
+	if _, err := w.Write(b[:ihdrEnd]); err != nil {
+		return err
+	}
+	for _, field := range Fields(&f.Metadata, info) {
+		data := make([]byte, 0, len(field.Key)+1+len(field.Value))
+		data = append(append(append(data, field.Key...), 0), field.Value...)
+		if err := writeChunk(w, "tEXt", data); err != nil {
+			return err
+		}
+	}
+	_, err := w.Write(b[ihdrEnd:])
+	return err
+}
+
+// ReadPNG reads a frame written by WritePNG.
+//
+// Any 16 bits grayscale PNG is accepted. The whole 16 bits range is kept as
+// is, e.g. for TLinear counts.
+func ReadPNG(r io.Reader) (*lepton.Frame, *Info, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Check the size before decoding, since it determines the allocation.
+	c, err := png.DecodeConfig(bytes.NewReader(b))
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.Width == 0 || c.Height == 0 || c.Width > maxSize || c.Height > maxSize {
+		return nil, nil, errors.New("frameio: invalid PNG size")
+	}
+	img, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, nil, err
+	}
+	g, ok := img.(*image.Gray16)
+	if !ok {
+		return nil, nil, errors.New("frameio: not a 16 bits grayscale PNG")
+	}
+	i := fromGray16(g)
+	var fields []Field
+	for b = b[len(pngSignature):]; len(b) >= 12; {
+		n := binary.BigEndian.Uint32(b)
+		if uint64(n)+12 > uint64(len(b)) {
+			break
+		}
+		if string(b[4:8]) == "tEXt" {
+			data := b[8 : 8+n]
+			if k := bytes.IndexByte(data, 0); k != -1 {
+				fields = append(fields, Field{string(data[:k]), string(data[k+1:])})
+			}
+		}
+		b = b[12+n:]
+	}
+	m, info, err := ParseFields(fields)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &lepton.Frame{Gray14: i, Metadata: m}, &info, nil
+}
+
+// Private details.
+
+const pngSignature = "\x89PNG\r\n\x1a\n"
+
+// writeChunk writes a PNG chunk.
+func writeChunk(w io.Writer, typ string, data []byte) error {
+	var hdr [8]byte
+	binary.BigEndian.PutUint32(hdr[:4], uint32(len(data)))
+	copy(hdr[4:], typ)
+	crc := crc32.NewIEEE()
+	crc.Write(hdr[4:])
+	crc.Write(data)
+	var footer [4]byte
+	binary.BigEndian.PutUint32(footer[:], crc.Sum32())
+	for _, b := range [][]byte{hdr[:], data, footer[:]} {
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toGray16 converts i to a Gray16 without scaling.
+func toGray16(i *image14bit.Gray14) *image.Gray16 {
+	b := i.Bounds()
+	dst := image.NewGray16(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		base := i.PixOffset(b.Min.X, y)
+		dBase := dst.PixOffset(b.Min.X, y)
+		for x, v := range i.Pix[base : base+b.Dx()] {
+			binary.BigEndian.PutUint16(dst.Pix[dBase+2*x:], v)
+		}
+	}
+	return dst
+}
+
+// fromGray16 converts g to a Gray14 without scaling.
+func fromGray16(g *image.Gray16) *image14bit.Gray14 {
+	b := g.Bounds()
+	i := image14bit.NewGray14(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		base := g.PixOffset(b.Min.X, y)
+		dBase := i.PixOffset(b.Min.X, y)
+		for x := 0; x < b.Dx(); x++ {
+			i.Pix[dBase+x] = binary.BigEndian.Uint16(g.Pix[base+2*x:])
+		}
+	}
+	return i
+}
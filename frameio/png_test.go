@@ -0,0 +1,80 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package frameio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"testing"
+)
+
+func TestPNG(t *testing.T) {
+	f, info := testFrame()
+	var buf bytes.Buffer
+	if err := WritePNG(&buf, f, info); err != nil {
+		t.Fatal(err)
+	}
+	// Must be readable by the standard library.
+	img, err := png.Decode(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, ok := img.(*image.Gray16); !ok || g.Gray16At(1, 0).Y != 7 {
+		t.Fatalf("%T", img)
+	}
+	got, gotInfo, err := ReadPNG(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equalFrames(t, f, got)
+	if !gotInfo.Time.Equal(info.Time) || gotInfo.Serial != info.Serial {
+		t.Fatalf("%+v", gotInfo)
+	}
+}
+
+func TestReadPNG_err(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ReadPNG(&buf); err == nil {
+		t.Fatal("8 bits PNG must be refused")
+	}
+	// A huge image compresses to a few bytes; patch the IHDR chunk instead of
+	// encoding one.
+	buf.Reset()
+	if err := png.Encode(&buf, image.NewGray16(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	b := buf.Bytes()
+	binary.BigEndian.PutUint32(b[16:], 1<<15)
+	binary.BigEndian.PutUint32(b[20:], 1<<15)
+	binary.BigEndian.PutUint32(b[29:], crc32.ChecksumIEEE(b[12:29]))
+	if _, err := png.DecodeConfig(bytes.NewReader(b)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ReadPNG(bytes.NewReader(b)); err == nil {
+		t.Fatal("huge PNG must be refused")
+	}
+}
+
+func TestPNG_tlinear(t *testing.T) {
+	// TLinear counts use the whole 16 bits; 29315 is 20°C.
+	f, info := testFrame()
+	f.Pix[0] = 29315
+	f.Pix[1] = 0xFFFF
+	var buf bytes.Buffer
+	if err := WritePNG(&buf, f, info); err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := ReadPNG(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equalFrames(t, f, got)
+}
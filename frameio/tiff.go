@@ -0,0 +1,303 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package frameio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"periph.io/x/periph/devices/lepton"
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// WriteTIFF writes the frame as an uncompressed 16 bits grayscale TIFF.
+//
+// The pixels are the raw intensities. The metadata is stored as
+// "Key=Value" lines in the ImageDescription tag, as returned by Fields. The
+// capture time is also stored in the DateTime tag. info may be nil.
+func WriteTIFF(w io.Writer, f *lepton.Frame, info *Info) error {
+	b := f.Bounds()
+	var desc strings.Builder
+	for _, field := range Fields(&f.Metadata, info) {
+		desc.WriteString(field.Key + "=" + field.Value + "\n")
+	}
+	pixels := make([]byte, 2*b.Dx()*b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		base := f.PixOffset(b.Min.X, y)
+		for x, v := range f.Pix[base : base+b.Dx()] {
+			binary.LittleEndian.PutUint16(pixels[2*((y-b.Min.Y)*b.Dx()+x):], v)
+		}
+	}
+	entries := []tiffEntry{
+		{tag: tagImageWidth, typ: tiffLong, values: []uint32{uint32(b.Dx())}},
+		{tag: tagImageLength, typ: tiffLong, values: []uint32{uint32(b.Dy())}},
+		{tag: tagBitsPerSample, typ: tiffShort, values: []uint32{16}},
+		{tag: tagCompression, typ: tiffShort, values: []uint32{1}},
+		{tag: tagPhotometric, typ: tiffShort, values: []uint32{1}},
+		{tag: tagImageDescription, typ: tiffASCII, ascii: desc.String()},
+		{tag: tagStripOffsets, typ: tiffLong, values: []uint32{0}},
+		{tag: tagSamplesPerPixel, typ: tiffShort, values: []uint32{1}},
+		{tag: tagRowsPerStrip, typ: tiffLong, values: []uint32{uint32(b.Dy())}},
+		{tag: tagStripByteCounts, typ: tiffLong, values: []uint32{uint32(len(pixels))}},
+		{tag: tagPlanarConfig, typ: tiffShort, values: []uint32{1}},
+		{tag: tagSoftware, typ: tiffASCII, ascii: "go-lepton"},
+		{tag: tagSampleFormat, typ: tiffShort, values: []uint32{1}},
+	}
+	if info != nil && !info.Time.IsZero() {
+		entries = append(entries, tiffEntry{tag: tagDateTime, typ: tiffASCII, ascii: info.Time.Format("2006:01:02 15:04:05")})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].tag < entries[j].tag })
+
+	// Layout: header, IFD, out of line values, pixels.
+	ifdSize := 2 + 12*len(entries) + 4
+	var extra []byte
+	offset := uint32(8 + ifdSize)
+	for n := range entries {
+		e := &entries[n]
+		if e.typ == tiffASCII {
+			e.data = append([]byte(e.ascii), 0)
+		} else {
+			for _, v := range e.values {
+				if e.typ == tiffShort {
+					e.data = append(e.data, byte(v), byte(v>>8))
+				} else {
+					e.data = append(e.data, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+				}
+			}
+		}
+		if len(e.data) > 4 {
+			e.offset = offset + uint32(len(extra))
+			extra = append(extra, e.data...)
+			if len(extra)%2 != 0 {
+				// Values must start on a word boundary.
+				extra = append(extra, 0)
+			}
+		}
+	}
+	pixelsOffset := offset + uint32(len(extra))
+	var out bytes.Buffer
+	out.WriteString("II")
+	binary.Write(&out, binary.LittleEndian, uint16(42))
+	binary.Write(&out, binary.LittleEndian, uint32(8))
+	binary.Write(&out, binary.LittleEndian, uint16(len(entries)))
+	for _, e := range entries {
+		if e.tag == tagStripOffsets {
+			binary.LittleEndian.PutUint32(e.data, pixelsOffset)
+		}
+		binary.Write(&out, binary.LittleEndian, e.tag)
+		binary.Write(&out, binary.LittleEndian, e.typ)
+		count := uint32(len(e.values))
+		if e.typ == tiffASCII {
+			count = uint32(len(e.data))
+		}
+		binary.Write(&out, binary.LittleEndian, count)
+		if len(e.data) > 4 {
+			binary.Write(&out, binary.LittleEndian, e.offset)
+		} else {
+			var v [4]byte
+			copy(v[:], e.data)
+			out.Write(v[:])
+		}
+	}
+	binary.Write(&out, binary.LittleEndian, uint32(0))
+	out.Write(extra)
+	out.Write(pixels)
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// ReadTIFF reads a frame written by WriteTIFF.
+//
+// Any uncompressed 16 bits grayscale TIFF is accepted. The whole 16 bits range
+// is kept as is, e.g. for TLinear counts. Only the first image is read.
+func ReadTIFF(r io.Reader) (*lepton.Frame, *Info, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(b) < 8 {
+		return nil, nil, errors.New("frameio: TIFF too short")
+	}
+	var order binary.ByteOrder
+	switch string(b[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil, nil, errors.New("frameio: not a TIFF")
+	}
+	if order.Uint16(b[2:]) != 42 {
+		return nil, nil, errors.New("frameio: not a TIFF")
+	}
+	t := tiffReader{b: b, order: order, tags: map[uint16][]byte{}, types: map[uint16]uint16{}}
+	if err := t.readIFD(order.Uint32(b[4:])); err != nil {
+		return nil, nil, err
+	}
+	for tag, want := range map[uint16]uint32{tagBitsPerSample: 16, tagCompression: 1, tagPhotometric: 1, tagSamplesPerPixel: 1} {
+		if v, _ := t.uint(tag, 1); v != want {
+			return nil, nil, fmt.Errorf("frameio: unsupported TIFF; tag %d is %d, expected %d", tag, v, want)
+		}
+	}
+	w, _ := t.uint(tagImageWidth, 0)
+	h, _ := t.uint(tagImageLength, 0)
+	if w == 0 || h == 0 || w > maxSize || h > maxSize {
+		return nil, nil, errors.New("frameio: invalid TIFF size")
+	}
+	offsets := t.uints(tagStripOffsets)
+	counts := t.uints(tagStripByteCounts)
+	if len(offsets) == 0 || len(offsets) != len(counts) {
+		return nil, nil, errors.New("frameio: invalid TIFF strips")
+	}
+	var pixels []byte
+	for n, o := range offsets {
+		if uint64(o)+uint64(counts[n]) > uint64(len(b)) {
+			return nil, nil, errors.New("frameio: TIFF truncated")
+		}
+		pixels = append(pixels, b[o:o+counts[n]]...)
+	}
+	if len(pixels) < int(2*w*h) {
+		return nil, nil, errors.New("frameio: TIFF truncated")
+	}
+	f := &lepton.Frame{Gray14: image14bit.NewGray14(image.Rect(0, 0, int(w), int(h)))}
+	for n := range f.Pix {
+		f.Pix[n] = order.Uint16(pixels[2*n:])
+	}
+	var fields []Field
+	for _, l := range strings.Split(t.ascii(tagImageDescription), "\n") {
+		if i := strings.IndexByte(l, '='); i != -1 {
+			fields = append(fields, Field{l[:i], l[i+1:]})
+		}
+	}
+	m, info, err := ParseFields(fields)
+	if err != nil {
+		return nil, nil, err
+	}
+	f.Metadata = m
+	return f, &info, nil
+}
+
+// Private details.
+
+// TIFF tags.
+const (
+	tagImageWidth       uint16 = 256
+	tagImageLength      uint16 = 257
+	tagBitsPerSample    uint16 = 258
+	tagCompression      uint16 = 259
+	tagPhotometric      uint16 = 262
+	tagImageDescription uint16 = 270
+	tagStripOffsets     uint16 = 273
+	tagSamplesPerPixel  uint16 = 277
+	tagRowsPerStrip     uint16 = 278
+	tagStripByteCounts  uint16 = 279
+	tagPlanarConfig     uint16 = 284
+	tagSoftware         uint16 = 305
+	tagDateTime         uint16 = 306
+	tagSampleFormat     uint16 = 339
+)
+
+// TIFF field types.
+const (
+	tiffASCII uint16 = 2
+	tiffShort uint16 = 3
+	tiffLong  uint16 = 4
+)
+
+// tiffEntry is an IFD entry being written.
+type tiffEntry struct {
+	tag    uint16
+	typ    uint16
+	values []uint32
+	ascii  string
+	data   []byte // Encoded values.
+	offset uint32 // Offset of data, when it doesn't fit in the entry.
+}
+
+// tiffReader decodes the tags of a TIFF file.
+type tiffReader struct {
+	b     []byte
+	order binary.ByteOrder
+	tags  map[uint16][]byte // Raw values.
+	types map[uint16]uint16
+}
+
+func (t *tiffReader) readIFD(offset uint32) error {
+	if uint64(offset)+2 > uint64(len(t.b)) {
+		return errors.New("frameio: TIFF truncated")
+	}
+	n := int(t.order.Uint16(t.b[offset:]))
+	if int(offset)+2+12*n > len(t.b) {
+		return errors.New("frameio: TIFF truncated")
+	}
+	for i := 0; i < n; i++ {
+		e := t.b[int(offset)+2+12*i:]
+		tag := t.order.Uint16(e)
+		typ := t.order.Uint16(e[2:])
+		count := t.order.Uint32(e[4:])
+		size := uint64(count)
+		switch typ {
+		case tiffShort:
+			size *= 2
+		case tiffLong:
+			size *= 4
+		case tiffASCII, 1, 7:
+		default:
+			// Unsupported types are skipped.
+			continue
+		}
+		data := e[8:12]
+		if size > 4 {
+			o := uint64(t.order.Uint32(e[8:]))
+			if o+size > uint64(len(t.b)) {
+				return errors.New("frameio: TIFF truncated")
+			}
+			data = t.b[o : o+size]
+		} else {
+			data = data[:size]
+		}
+		t.tags[tag] = data
+		t.types[tag] = typ
+	}
+	return nil
+}
+
+// uints returns the values of a SHORT or LONG tag.
+func (t *tiffReader) uints(tag uint16) []uint32 {
+	data := t.tags[tag]
+	var out []uint32
+	switch t.types[tag] {
+	case tiffShort:
+		for i := 0; i+2 <= len(data); i += 2 {
+			out = append(out, uint32(t.order.Uint16(data[i:])))
+		}
+	case tiffLong:
+		for i := 0; i+4 <= len(data); i += 4 {
+			out = append(out, t.order.Uint32(data[i:]))
+		}
+	}
+	return out
+}
+
+// uint returns the single value of a tag, or def if absent.
+func (t *tiffReader) uint(tag uint16, def uint32) (uint32, bool) {
+	v := t.uints(tag)
+	if len(v) == 0 {
+		return def, false
+	}
+	return v[0], true
+}
+
+// ascii returns the value of an ASCII tag.
+func (t *tiffReader) ascii(tag uint16) string {
+	return strings.TrimRight(string(t.tags[tag]), "\x00")
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package frameio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestTIFF(t *testing.T) {
+	f, info := testFrame()
+	var buf bytes.Buffer
+	if err := WriteTIFF(&buf, f, info); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("II*\x00")) {
+		t.Fatal("invalid header")
+	}
+	got, gotInfo, err := ReadTIFF(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	equalFrames(t, f, got)
+	if !gotInfo.Time.Equal(info.Time) || gotInfo.Serial != info.Serial {
+		t.Fatalf("%+v", gotInfo)
+	}
+}
+
+func TestTIFF_tlinear(t *testing.T) {
+	// TLinear counts use the whole 16 bits; 29315 is 20°C.
+	f, info := testFrame()
+	f.Pix[0] = 29315
+	f.Pix[1] = 0xFFFF
+	var buf bytes.Buffer
+	if err := WriteTIFF(&buf, f, info); err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := ReadTIFF(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	equalFrames(t, f, got)
+}
+
+func TestReadTIFF_bigEndian(t *testing.T) {
+	// A 2x1 image, written by hand.
+	var b bytes.Buffer
+	w := func(v ...interface{}) {
+		for _, x := range v {
+			binary.Write(&b, binary.BigEndian, x)
+		}
+	}
+	b.WriteString("MM")
+	w(uint16(42), uint32(8))
+	entries := [][3]uint32{
+		{256, 3, 2}, {257, 3, 1}, {258, 3, 16}, {259, 3, 1}, {262, 3, 1},
+		{277, 3, 1}, {278, 3, 1},
+	}
+	w(uint16(len(entries) + 2))
+	for _, e := range entries {
+		w(uint16(e[0]), uint16(e[1]), uint32(1), uint16(e[2]), uint16(0))
+	}
+	pixels := uint32(8 + 2 + 12*(len(entries)+2) + 4)
+	// A single strip.
+	w(uint16(273), uint16(4), uint32(1), pixels)
+	w(uint16(279), uint16(4), uint32(1), uint32(4))
+	w(uint32(0))
+	w(uint16(1000), uint16(16383))
+	f, info, err := ReadTIFF(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Bounds().Dx() != 2 || f.Pix[0] != 1000 || f.Pix[1] != 16383 {
+		t.Fatal(f.Bounds(), f.Pix)
+	}
+	if !info.Time.IsZero() {
+		t.Fatal(info)
+	}
+}
+
+func TestReadTIFF_err(t *testing.T) {
+	for _, s := range []string{"", "PK\x03\x04", "II\x2b\x00\x08\x00\x00\x00", "II*\x00\xff\x00\x00\x00"} {
+		if _, _, err := ReadTIFF(bytes.NewReader([]byte(s))); err == nil {
+			t.Fatalf("%q: expected error", s)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gray14
+
+import (
+	"image"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// CLAHEOpts are the parameters for CLAHE.
+type CLAHEOpts struct {
+	// TilesX and TilesY is the number of tiles in the grid. Each tile gets its
+	// own histogram.
+	TilesX int
+	TilesY int
+	// ClipLimit is the maximum population of a histogram bin, relative to a
+	// uniform distribution over the tile's intensity range. 1 means a linear
+	// mapping per tile, higher values increase the contrast. 0 disables
+	// clipping.
+	ClipLimit float64
+}
+
+// DefaultCLAHEOpts is a 8x6 grid, which creates 10x10 tiles on a Lepton.
+var DefaultCLAHEOpts = CLAHEOpts{
+	TilesX:    8,
+	TilesY:    6,
+	ClipLimit: 3,
+}
+
+// CLAHE reduces the dynamic range of a 14 bits down to 8 bits with contrast
+// limited adaptive histogram equalization.
+//
+// It brings up local details that a global AGC like AGCLinear hides. If opts
+// is nil, DefaultCLAHEOpts is used.
+func CLAHE(i *image14bit.Gray14, opts *CLAHEOpts) *image.Gray {
+	b := i.Bounds()
+	dst := image.NewGray(b)
+	if b.Empty() {
+		return dst
+	}
+	c := newClahe(i, opts)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		base := i.PixOffset(b.Min.X, y)
+		dBase := dst.PixOffset(b.Min.X, y)
+		for x, j := range i.Pix[base : base+b.Dx()] {
+			dst.Pix[dBase+x] = c.at(x, y-b.Min.Y, image14bit.Intensity14(j))
+		}
+	}
+	return dst
+}
+
+// CLAHERGB is the same as CLAHE but maps the output to the default palette.
+//
+// Use Palette.Colorize on the output of CLAHE to use another palette.
+func CLAHERGB(i *image14bit.Gray14, opts *CLAHEOpts) *image.NRGBA {
+	return DefaultPalette.Colorize(CLAHE(i, opts))
+}
+
+// Private details.
+
+type clahe struct {
+	opts   CLAHEOpts
+	w, h   int
+	lo     int
+	span   int       // Number of counts in the populated range.
+	luts   [][]uint8 // One per tile, row major; indexed by bin().
+	tileX0 []int     // Tile boundaries; len is TilesX+1.
+	tileY0 []int     // Tile boundaries; len is TilesY+1.
+}
+
+func newClahe(i *image14bit.Gray14, opts *CLAHEOpts) *clahe {
+	b := i.Bounds()
+	c := &clahe{opts: DefaultCLAHEOpts, w: b.Dx(), h: b.Dy()}
+	if opts != nil {
+		c.opts = *opts
+	}
+	if c.opts.TilesX < 1 {
+		c.opts.TilesX = 1
+	} else if c.opts.TilesX > c.w {
+		c.opts.TilesX = c.w
+	}
+	if c.opts.TilesY < 1 {
+		c.opts.TilesY = 1
+	} else if c.opts.TilesY > c.h {
+		c.opts.TilesY = c.h
+	}
+	c.tileX0 = make([]int, c.opts.TilesX+1)
+	for n := range c.tileX0 {
+		c.tileX0[n] = n * c.w / c.opts.TilesX
+	}
+	c.tileY0 = make([]int, c.opts.TilesY+1)
+	for n := range c.tileY0 {
+		c.tileY0[n] = n * c.h / c.opts.TilesY
+	}
+
+	// Each LUT covers the whole populated range, one bin per count.
+	hi := 0
+	c.lo = histSize
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		base := i.PixOffset(b.Min.X, y)
+		for _, j := range i.Pix[base : base+b.Dx()] {
+			v := int(j)
+			if v < c.lo {
+				c.lo = v
+			}
+			if v > hi {
+				hi = v
+			}
+		}
+	}
+	c.span = hi - c.lo + 1
+
+	hist := make([]int, c.span)
+	c.luts = make([][]uint8, c.opts.TilesX*c.opts.TilesY)
+	for ty := 0; ty < c.opts.TilesY; ty++ {
+		for tx := 0; tx < c.opts.TilesX; tx++ {
+			for n := range hist {
+				hist[n] = 0
+			}
+			tlo, thi := c.span, 0
+			for y := c.tileY0[ty]; y < c.tileY0[ty+1]; y++ {
+				base := i.PixOffset(b.Min.X, b.Min.Y+y)
+				for _, j := range i.Pix[base+c.tileX0[tx] : base+c.tileX0[tx+1]] {
+					n := c.bin(image14bit.Intensity14(j))
+					hist[n]++
+					if n < tlo {
+						tlo = n
+					}
+					if n > thi {
+						thi = n
+					}
+				}
+			}
+			pixels := (c.tileX0[tx+1] - c.tileX0[tx]) * (c.tileY0[ty+1] - c.tileY0[ty])
+			c.luts[ty*c.opts.TilesX+tx] = c.lut(hist, tlo, thi, pixels)
+		}
+	}
+	return c
+}
+
+func (c *clahe) bin(j image14bit.Intensity14) int {
+	return int(j) - c.lo
+}
+
+// lut clips the histogram, redistributes the excess over the tile's range
+// [lo, hi] and returns the cumulative distribution scaled to 8 bits.
+func (c *clahe) lut(hist []int, lo, hi, pixels int) []uint8 {
+	if c.opts.ClipLimit > 0 {
+		bins := hi - lo + 1
+		limit := int(c.opts.ClipLimit * float64(pixels) / float64(bins))
+		if limit < 1 {
+			limit = 1
+		}
+		excess := 0
+		for n := lo; n <= hi; n++ {
+			if hist[n] > limit {
+				excess += hist[n] - limit
+				hist[n] = limit
+			}
+		}
+		inc, rem := excess/bins, excess%bins
+		for n := lo; n <= hi; n++ {
+			hist[n] += inc
+			if n-lo < rem {
+				hist[n]++
+			}
+		}
+	}
+	out := make([]uint8, len(hist))
+	sum := 0
+	for n, v := range hist {
+		sum += v
+		out[n] = uint8(sum * 255 / pixels)
+	}
+	return out
+}
+
+// at returns the output value for the intensity j at (x, y) relative to the
+// image origin, bilinearly interpolated between the four nearest tiles.
+func (c *clahe) at(x, y int, j image14bit.Intensity14) uint8 {
+	tx0, tx1, wx := c.neighbors(x, c.tileX0)
+	ty0, ty1, wy := c.neighbors(y, c.tileY0)
+	bin := c.bin(image14bit.Intensity14(j))
+	tiles := c.opts.TilesX
+	v00 := float64(c.luts[ty0*tiles+tx0][bin])
+	v01 := float64(c.luts[ty0*tiles+tx1][bin])
+	v10 := float64(c.luts[ty1*tiles+tx0][bin])
+	v11 := float64(c.luts[ty1*tiles+tx1][bin])
+	top := v00 + (v01-v00)*wx
+	bottom := v10 + (v11-v10)*wx
+	return uint8(top + (bottom-top)*wy + 0.5)
+}
+
+// neighbors returns the two tiles surrounding position p along one axis and
+// the weight of the second one.
+func (c *clahe) neighbors(p int, bounds []int) (int, int, float64) {
+	center := func(t int) float64 {
+		return float64(bounds[t]+bounds[t+1]-1) / 2
+	}
+	last := len(bounds) - 2
+	f := float64(p)
+	if f <= center(0) {
+		return 0, 0, 0
+	}
+	if f >= center(last) {
+		return last, last, 0
+	}
+	t := 0
+	for t < last && center(t+1) <= f {
+		t++
+	}
+	return t, t + 1, (f - center(t)) / (center(t+1) - center(t))
+}
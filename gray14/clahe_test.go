@@ -0,0 +1,62 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gray14
+
+import (
+	"image"
+	"testing"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+func TestCLAHE(t *testing.T) {
+	i := image14bit.NewGray14(image.Rect(0, 0, 80, 60))
+	// Left half is a cold wall with faint detail, right half is much hotter.
+	for y := 0; y < 60; y++ {
+		for x := 0; x < 80; x++ {
+			v := image14bit.Intensity14(8000 + (x+y)%4)
+			if x >= 40 {
+				v = 9000 + image14bit.Intensity14((x+y)%4)
+			}
+			i.SetIntensity14(x, y, v)
+		}
+	}
+	dst := CLAHE(i, nil)
+	if dst.Bounds() != i.Bounds() {
+		t.Fatal(dst.Bounds())
+	}
+	// With a global stretch, the wall detail would be within ~1 level.
+	lo, hi := dst.GrayAt(0, 0).Y, dst.GrayAt(3, 0).Y
+	if int(hi)-int(lo) < 64 {
+		t.Fatalf("%d - %d", hi, lo)
+	}
+	c := CLAHERGB(i, nil)
+	if c.Bounds() != i.Bounds() || c.Pix[3] != 255 {
+		t.Fatal("unexpected RGB output")
+	}
+}
+
+func TestCLAHETLinear(t *testing.T) {
+	// TLinear counts use the whole 16 bits; 29315 is 20°C.
+	i := image14bit.NewGray14(image.Rect(0, 0, 16, 16))
+	for n := range i.Pix {
+		i.Pix[n] = uint16(29300 + n)
+	}
+	dst := CLAHE(i, &CLAHEOpts{TilesX: 1, TilesY: 1})
+	if lo, hi := dst.Pix[0], dst.Pix[len(dst.Pix)-1]; int(hi)-int(lo) < 200 {
+		t.Fatalf("%d - %d", hi, lo)
+	}
+}
+
+func TestCLAHEUniform(t *testing.T) {
+	i := image14bit.NewGray14(image.Rect(0, 0, 10, 10))
+	for n := range i.Pix {
+		i.Pix[n] = 8192
+	}
+	opts := CLAHEOpts{TilesX: 20, TilesY: 0, ClipLimit: 2}
+	if dst := CLAHE(i, &opts); dst.Bounds() != i.Bounds() {
+		t.Fatal(dst.Bounds())
+	}
+}
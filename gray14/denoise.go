@@ -0,0 +1,183 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gray14
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// Denoiser reduces the spatial noise of a 14 bits image. It returns a new
+// image and leaves the source intact.
+type Denoiser func(i *image14bit.Gray14) *image14bit.Gray14
+
+// DenoiserByName returns one of "median", "gaussian" or "bilateral" with
+// parameters suitable for a Lepton.
+func DenoiserByName(name string) (Denoiser, error) {
+	switch name {
+	case "median":
+		return func(i *image14bit.Gray14) *image14bit.Gray14 {
+			return Median(i, 1)
+		}, nil
+	case "gaussian":
+		return func(i *image14bit.Gray14) *image14bit.Gray14 {
+			return Gaussian(i, 0.8)
+		}, nil
+	case "bilateral":
+		return func(i *image14bit.Gray14) *image14bit.Gray14 {
+			return Bilateral(i, 1.5, 20)
+		}, nil
+	default:
+		return nil, fmt.Errorf("gray14: unknown denoiser %q", name)
+	}
+}
+
+// DenoiserNames returns the names accepted by DenoiserByName.
+func DenoiserNames() []string {
+	return []string{"bilateral", "gaussian", "median"}
+}
+
+// Median replaces each pixel with the median of the (2*radius+1)² pixels
+// around it.
+//
+// It is very effective against isolated hot or dead pixels.
+func Median(i *image14bit.Gray14, radius int) *image14bit.Gray14 {
+	b := i.Bounds()
+	dst := image14bit.NewGray14(b)
+	window := make([]image14bit.Intensity14, 0, (2*radius+1)*(2*radius+1))
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			window = window[:0]
+			for dy := -radius; dy <= radius; dy++ {
+				for dx := -radius; dx <= radius; dx++ {
+					window = append(window, clampedAt(i, x+dx, y+dy))
+				}
+			}
+			dst.Pix[dst.PixOffset(x, y)] = uint16(MedianOf(window))
+		}
+	}
+	return dst
+}
+
+// MedianOf sorts v in place and returns its median.
+//
+// v must not be empty.
+func MedianOf(v []image14bit.Intensity14) image14bit.Intensity14 {
+	sort.Sort(intensities(v))
+	return v[len(v)/2]
+}
+
+// Gaussian blurs the image with a gaussian kernel of standard deviation
+// sigma, in pixels.
+func Gaussian(i *image14bit.Gray14, sigma float64) *image14bit.Gray14 {
+	b := i.Bounds()
+	if sigma <= 0 {
+		dst := image14bit.NewGray14(b)
+		copyGray14(dst, i)
+		return dst
+	}
+	radius := int(math.Ceil(3 * sigma))
+	kernel := make([]float64, 2*radius+1)
+	sum := 0.
+	for n := range kernel {
+		d := float64(n - radius)
+		kernel[n] = math.Exp(-d * d / (2 * sigma * sigma))
+		sum += kernel[n]
+	}
+	for n := range kernel {
+		kernel[n] /= sum
+	}
+	// Separable; horizontal then vertical.
+	w, h := b.Dx(), b.Dy()
+	tmp := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := 0.
+			for n, k := range kernel {
+				v += k * float64(clampedAt(i, b.Min.X+x+n-radius, b.Min.Y+y))
+			}
+			tmp[y*w+x] = v
+		}
+	}
+	dst := image14bit.NewGray14(b)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := 0.
+			for n, k := range kernel {
+				v += k * tmp[clampi(y+n-radius, h)*w+x]
+			}
+			dst.Pix[dst.PixOffset(b.Min.X+x, b.Min.Y+y)] = uint16(clampf(float32(v), math.MaxUint16))
+		}
+	}
+	return dst
+}
+
+// Bilateral is an edge preserving blur.
+//
+// sigmaSpatial is the standard deviation of the spatial gaussian in pixels.
+// sigmaRange is the standard deviation of the intensity gaussian in counts;
+// neighbors that differ by much more than sigmaRange are barely blended in,
+// keeping edges sharp.
+func Bilateral(i *image14bit.Gray14, sigmaSpatial, sigmaRange float64) *image14bit.Gray14 {
+	b := i.Bounds()
+	dst := image14bit.NewGray14(b)
+	if sigmaSpatial <= 0 || sigmaRange <= 0 {
+		copyGray14(dst, i)
+		return dst
+	}
+	radius := int(math.Ceil(2 * sigmaSpatial))
+	size := 2*radius + 1
+	spatial := make([]float64, size*size)
+	for dy := -radius; dy <= radius; dy++ {
+		for dx := -radius; dx <= radius; dx++ {
+			spatial[(dy+radius)*size+dx+radius] = math.Exp(-float64(dx*dx+dy*dy) / (2 * sigmaSpatial * sigmaSpatial))
+		}
+	}
+	rangeDiv := 2 * sigmaRange * sigmaRange
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			center := float64(i.Pix[i.PixOffset(x, y)])
+			sum, weights := 0., 0.
+			for dy := -radius; dy <= radius; dy++ {
+				for dx := -radius; dx <= radius; dx++ {
+					v := float64(clampedAt(i, x+dx, y+dy))
+					d := v - center
+					w := spatial[(dy+radius)*size+dx+radius] * math.Exp(-d*d/rangeDiv)
+					sum += w * v
+					weights += w
+				}
+			}
+			dst.Pix[dst.PixOffset(x, y)] = uint16(clampf(float32(sum/weights), math.MaxUint16))
+		}
+	}
+	return dst
+}
+
+// Private details.
+
+type intensities []image14bit.Intensity14
+
+func (s intensities) Len() int           { return len(s) }
+func (s intensities) Less(i, j int) bool { return s[i] < s[j] }
+func (s intensities) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// clampedAt returns the pixel at (x, y), repeating the edges of the image.
+func clampedAt(i *image14bit.Gray14, x, y int) image14bit.Intensity14 {
+	b := i.Bounds()
+	x = b.Min.X + clampi(x-b.Min.X, b.Dx())
+	y = b.Min.Y + clampi(y-b.Min.Y, b.Dy())
+	return image14bit.Intensity14(i.Pix[i.PixOffset(x, y)])
+}
+
+// copyGray14 copies src into dst, which must have the same bounds.
+func copyGray14(dst, src *image14bit.Gray14) {
+	b := src.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		copy(dst.Pix[dst.PixOffset(b.Min.X, y):], src.Pix[src.PixOffset(b.Min.X, y):src.PixOffset(b.Min.X, y)+b.Dx()])
+	}
+}
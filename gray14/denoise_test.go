@@ -0,0 +1,101 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gray14
+
+import (
+	"image"
+	"testing"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// edge returns an image with a sharp vertical edge and one hot pixel.
+func edge() *image14bit.Gray14 {
+	i := image14bit.NewGray14(image.Rect(0, 0, 20, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 20; x++ {
+			v := image14bit.Intensity14(8000)
+			if x >= 10 {
+				v = 12000
+			}
+			i.SetIntensity14(x, y, v)
+		}
+	}
+	i.SetIntensity14(4, 4, 16000)
+	return i
+}
+
+func TestMedian(t *testing.T) {
+	dst := Median(edge(), 1)
+	if v := dst.Intensity14At(4, 4); v != 8000 {
+		t.Fatal(v)
+	}
+	if v := dst.Intensity14At(9, 5); v != 8000 {
+		t.Fatal(v)
+	}
+	if v := dst.Intensity14At(10, 5); v != 12000 {
+		t.Fatal(v)
+	}
+}
+
+func TestMedianOf(t *testing.T) {
+	v := []image14bit.Intensity14{30, 10, 20, 50, 40}
+	if m := MedianOf(v); m != 30 {
+		t.Fatal(m)
+	}
+	if v[0] != 10 || v[4] != 50 {
+		t.Fatal(v)
+	}
+}
+
+func TestGaussian(t *testing.T) {
+	dst := Gaussian(edge(), 1)
+	if v := dst.Intensity14At(4, 4); v <= 8000 || v >= 16000 {
+		t.Fatal(v)
+	}
+	if v := dst.Intensity14At(10, 5); v <= 8000 || v >= 12000 {
+		t.Fatal(v)
+	}
+	// Doesn't clip to 8 bits; the far field is intact.
+	if v := dst.Intensity14At(18, 0); v != 12000 {
+		t.Fatal(v)
+	}
+}
+
+func TestDenoiseTLinear(t *testing.T) {
+	// TLinear counts use the whole 16 bits; 29315 is 20°C.
+	i := image14bit.NewGray14(image.Rect(0, 0, 5, 5))
+	for n := range i.Pix {
+		i.Pix[n] = 29315
+	}
+	if v := Gaussian(i, 1).Intensity14At(2, 2); v != 29315 {
+		t.Fatal(v)
+	}
+	if v := Bilateral(i, 1, 100).Intensity14At(2, 2); v != 29315 {
+		t.Fatal(v)
+	}
+}
+
+func TestBilateral(t *testing.T) {
+	i := edge()
+	i.SetIntensity14(15, 5, 12010)
+	dst := Bilateral(i, 1.5, 20)
+	// The edge stays sharp.
+	if v := dst.Intensity14At(9, 5); v != 8000 {
+		t.Fatal(v)
+	}
+	if v := dst.Intensity14At(10, 5); v != 12000 {
+		t.Fatal(v)
+	}
+	// Small noise is smoothed.
+	if v := dst.Intensity14At(15, 5); v >= 12010 || v < 12000 {
+		t.Fatal(v)
+	}
+	for _, n := range DenoiserNames() {
+		if _, err := DenoiserByName(n); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
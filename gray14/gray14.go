@@ -2,26 +2,33 @@
 // Use of this source code is governed under the Apache License, Version 2.0
 // that can be found in the LICENSE file.
 
-// Package gray14 implements functions specifically to manipulate image.Gray16
-// containing a 14 bits intensity image.
+// Package gray14 implements functions specifically to manipulate
+// image14bit.Gray14 containing a 14 bits intensity image.
+//
+// Since lepton.Frame embeds a *image14bit.Gray14, frames can be passed
+// directly without conversion.
 package gray14
 
 import (
 	"fmt"
 	"image"
 	"image/color"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
 )
 
 // Min returns the lowest intensity pixel of the image.
 //
-// Ignores pixels of less than 100 in intensity.
-func Min(i *image.Gray16) uint16 {
-	out := uint16(0xffff)
+// Ignores pixels of less than DefaultDeadThreshold in intensity. Use Stats to
+// get control over the threshold.
+func Min(i *image14bit.Gray14) image14bit.Intensity14 {
+	out := image14bit.Intensity14(0xffff)
 	b := i.Bounds()
 	for y := b.Min.Y; y < b.Max.Y; y++ {
-		for x := b.Min.X; x < b.Max.X; x++ {
-			if j := i.Gray16At(x, y).Y; j >= 100 && j < out {
-				out = j
+		base := i.PixOffset(b.Min.X, y)
+		for _, j := range i.Pix[base : base+b.Dx()] {
+			if v := image14bit.Intensity14(j); v >= DefaultDeadThreshold && v < out {
+				out = v
 			}
 		}
 	}
@@ -29,13 +36,14 @@ func Min(i *image.Gray16) uint16 {
 }
 
 // Max returns the highest intensity pixel of the image.
-func Max(i *image.Gray16) uint16 {
-	out := uint16(0)
+func Max(i *image14bit.Gray14) image14bit.Intensity14 {
+	out := image14bit.Intensity14(0)
 	b := i.Bounds()
 	for y := b.Min.Y; y < b.Max.Y; y++ {
-		for x := b.Min.X; x < b.Max.X; x++ {
-			if j := i.Gray16At(x, y).Y; j > out {
-				out = j
+		base := i.PixOffset(b.Min.X, y)
+		for _, j := range i.Pix[base : base+b.Dx()] {
+			if v := image14bit.Intensity14(j); v > out {
+				out = v
 			}
 		}
 	}
@@ -43,22 +51,24 @@ func Max(i *image.Gray16) uint16 {
 }
 
 // Diff encodes the difference in the image as a 8 bit image centered at 128.
-func Diff(a, b *image.Gray16) *image.Gray {
+func Diff(a, b *image14bit.Gray14) *image.Gray {
 	bounds := a.Bounds()
 	if bounds != b.Bounds() {
 		return nil
 	}
 	dst := image.NewGray(bounds)
 	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
-		base := y * bounds.Dx()
-		for x := bounds.Min.X; x < bounds.Max.X; x++ {
-			i := int(a.Gray16At(x, y).Y) - int(b.Gray16At(x, y).Y)
+		aBase := a.PixOffset(bounds.Min.X, y)
+		bBase := b.PixOffset(bounds.Min.X, y)
+		dBase := dst.PixOffset(bounds.Min.X, y)
+		for x := 0; x < bounds.Dx(); x++ {
+			i := int(a.Pix[aBase+x]) - int(b.Pix[bBase+x])
 			if i > 127 {
 				i = 127
 			} else if i < -128 {
 				i = -128
 			}
-			dst.Pix[base+x] = uint8(i + 128)
+			dst.Pix[dBase+x] = uint8(i + 128)
 		}
 	}
 	return dst
@@ -66,15 +76,24 @@ func Diff(a, b *image.Gray16) *image.Gray {
 
 // AGCLinear reduces the dynamic range of a 14 bits down to 8 bits very naively
 // without gamma.
-func AGCLinear(i *image.Gray16) *image.Gray {
+//
+// A uniform image is black.
+func AGCLinear(i *image14bit.Gray14) *image.Gray {
 	b := i.Bounds()
 	dst := image.NewGray(b)
-	floor := Min(i)
-	delta := int(Max(i) - floor)
+	floor := int(Min(i))
+	delta := int(Max(i)) - floor
+	if delta <= 0 {
+		return dst
+	}
 	for y := b.Min.Y; y < b.Max.Y; y++ {
-		base := y * b.Dx()
-		for x := b.Min.X; x < b.Max.X; x++ {
-			dst.Pix[base+x] = uint8(int(i.Gray16At(x, y).Y-floor) * 255 / delta)
+		base := i.PixOffset(b.Min.X, y)
+		dBase := dst.PixOffset(b.Min.X, y)
+		for x, j := range i.Pix[base : base+b.Dx()] {
+			// Dead pixels are below the floor.
+			if v := int(j) - floor; v > 0 {
+				dst.Pix[dBase+x] = uint8(v * 255 / delta)
+			}
 		}
 	}
 	return dst
@@ -86,7 +105,7 @@ func AGCLinear(i *image.Gray16) *image.Gray {
 // TODO(maruel): Confirm it's real.
 // With room temperature of 20C° and precision per unit of 0.025°K, range is
 // 512*0.025 = 12.8. (?)
-func ToRGB(intensity uint16) color.NRGBA {
+func ToRGB(intensity image14bit.Intensity14) color.NRGBA {
 	// Range is [-255, 255].
 	i := (int(intensity) - 8192)
 	if i < 0 {
@@ -116,26 +135,33 @@ func ToRGB(intensity uint16) color.NRGBA {
 
 // PseudoColor reduces the dynamic range of a 14 bits down to RGB. It doesn't
 // apply AGC.
-func PseudoColor(i *image.Gray16) *image.NRGBA {
+//
+// Use Palette.PseudoColor to use another palette than ToRGB.
+func PseudoColor(i *image14bit.Gray14) *image.NRGBA {
 	b := i.Bounds()
 	dst := image.NewNRGBA(b)
 	for y := b.Min.Y; y < b.Max.Y; y++ {
-		for x := b.Min.X; x < b.Max.X; x++ {
-			dst.SetNRGBA(x, y, ToRGB(i.Gray16At(x, y).Y))
+		base := i.PixOffset(b.Min.X, y)
+		for x, j := range i.Pix[base : base+b.Dx()] {
+			dst.SetNRGBA(b.Min.X+x, y, ToRGB(image14bit.Intensity14(j)))
 		}
 	}
 	return dst
 }
 
 // Equal returns true if the two frames are equal.
-func Equal(a, b *image.Gray16) bool {
+func Equal(a, b *image14bit.Gray14) bool {
 	bounds := a.Bounds()
 	if bounds != b.Bounds() {
 		return false
 	}
-	for i := 0; i < len(a.Pix); i++ {
-		if a.Pix[i] != b.Pix[i] {
-			return false
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		aBase := a.PixOffset(bounds.Min.X, y)
+		bBase := b.PixOffset(bounds.Min.X, y)
+		for x := 0; x < bounds.Dx(); x++ {
+			if a.Pix[aBase+x] != b.Pix[bBase+x] {
+				return false
+			}
 		}
 	}
 	return true
@@ -154,26 +180,17 @@ func PaletteGray(vertical bool) *image.Gray {
 	return dst
 }
 
-// PaletteRGB returns the default colorful palette.
+// PaletteRGB returns a strip of the colors of DefaultPalette.
 func PaletteRGB(vertical bool) *image.NRGBA {
-	x, y := 256, 1
-	if vertical {
-		x, y = y, x
-	}
-	dst := image.NewNRGBA(image.Rect(0, 0, x, y))
-	for x := 0; x < 256; x++ {
-		dstBase := 4 * x
-		palBase := 3 * x
-		dst.Pix[dstBase] = palette[palBase]
-		dst.Pix[dstBase+1] = palette[palBase+1]
-		dst.Pix[dstBase+2] = palette[palBase+2]
-		dst.Pix[dstBase+3] = 255
-	}
-	return dst
+	return DefaultPalette.Strip(vertical)
 }
 
 // Private details.
 
+// pseudoColorFloor is the lowest intensity mapped by Palette.PseudoColor.
+const pseudoColorFloor = 8192 - 256
+
+// palette is the table for DefaultPalette.
 var palette = []uint8{
 	255, 255, 255, 253, 253, 253, 251, 251, 251, 249, 249, 249, 247, 247, 247,
 	245, 245, 245, 243, 243, 243, 241, 241, 241, 239, 239, 239, 237, 237, 237,
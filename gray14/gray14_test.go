@@ -7,11 +7,83 @@ package gray14
 import (
 	"image"
 	"testing"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
 )
 
 func TestMin(t *testing.T) {
-	i := image.NewGray16(image.Rect(0, 0, 1, 1))
+	i := image14bit.NewGray14(image.Rect(0, 0, 1, 1))
 	if m := Min(i); m != 65535 {
 		t.Fatal(m)
 	}
 }
+
+func TestMinMax(t *testing.T) {
+	i := image14bit.NewGray14(image.Rect(0, 0, 4, 2))
+	for n := range i.Pix {
+		i.Pix[n] = uint16(8000 + 10*n)
+	}
+	i.Pix[3] = 50
+	if m := Min(i); m != 8000 {
+		t.Fatal(m)
+	}
+	if m := Max(i); m != 8070 {
+		t.Fatal(m)
+	}
+}
+
+func TestAGCLinear(t *testing.T) {
+	i := image14bit.NewGray14(image.Rect(0, 0, 3, 1))
+	// Uniform, or all dead pixels.
+	for _, v := range []uint16{0, 29315} {
+		for n := range i.Pix {
+			i.Pix[n] = v
+		}
+		if dst := AGCLinear(i); dst.Pix[0] != 0 || dst.Pix[2] != 0 {
+			t.Fatal(v, dst.Pix)
+		}
+	}
+	i.Pix[0] = 10
+	i.Pix[1] = 29315
+	i.Pix[2] = 0xFFFF
+	if dst := AGCLinear(i); dst.Pix[0] != 0 || dst.Pix[1] != 0 || dst.Pix[2] != 255 {
+		t.Fatal(dst.Pix)
+	}
+}
+
+func TestPseudoColor(t *testing.T) {
+	i := image14bit.NewGray14(image.Rect(0, 0, 2, 1))
+	i.Pix[0] = 8000
+	i.Pix[1] = 8300
+	dst := PseudoColor(i)
+	if dst.NRGBAAt(0, 0) != ToRGB(8000) || dst.NRGBAAt(1, 0) != ToRGB(8300) {
+		t.Fatal(dst.Pix)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := image14bit.NewGray14(image.Rect(0, 0, 3, 1))
+	b := image14bit.NewGray14(image.Rect(0, 0, 3, 1))
+	a.Pix[0], b.Pix[0] = 8192, 8192
+	a.Pix[1], b.Pix[1] = 8200, 8190
+	a.Pix[2], b.Pix[2] = 8000, 9000
+	d := Diff(a, b)
+	if d.Pix[0] != 128 || d.Pix[1] != 138 || d.Pix[2] != 0 {
+		t.Fatal(d.Pix)
+	}
+	if Diff(a, image14bit.NewGray14(image.Rect(0, 0, 1, 1))) != nil {
+		t.Fatal("expected nil on mismatched bounds")
+	}
+}
+
+func TestEqual(t *testing.T) {
+	a := image14bit.NewGray14(image.Rect(0, 0, 2, 2))
+	b := image14bit.NewGray14(image.Rect(0, 0, 2, 2))
+	if !Equal(a, b) {
+		t.Fatal("expected equal")
+	}
+	b.Pix[3] = 1
+	if Equal(a, b) {
+		t.Fatal("expected different")
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gray14
+
+import (
+	"image"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// HEQOpts are the parameters for HEQ.
+//
+// They mirror the HEQ parameters of the AGC module in the Lepton's IDD.
+type HEQOpts struct {
+	// ClipLimitHigh is the maximum population of a single histogram bin. It
+	// limits how much of the output range a large uniform area can take.
+	ClipLimitHigh int
+	// ClipLimitLow is an artificial population added to every non-empty
+	// histogram bin. It ensures sparse bins still get some output range.
+	ClipLimitLow int
+	// LinearPercent is the percentage [0, 100] of the output range allocated
+	// to a linear mapping instead of the equalized one.
+	LinearPercent int
+	// EmptyCounts is the population at or below which a bin is treated as
+	// empty. It keeps a few dead or hot pixels from stretching the range.
+	EmptyCounts int
+	// OutputMin and OutputMax is the output range.
+	OutputMin uint8
+	OutputMax uint8
+	// Damping is the temporal damping [0, 256] of the transfer function
+	// across successive frames. 0 means no damping.
+	Damping int
+}
+
+// DefaultHEQOpts are the defaults used by the Lepton.
+var DefaultHEQOpts = HEQOpts{
+	ClipLimitHigh: 80 * 60,
+	ClipLimitLow:  512,
+	LinearPercent: 20,
+	EmptyCounts:   2,
+	OutputMin:     0,
+	OutputMax:     255,
+	Damping:       64,
+}
+
+// HEQ is a histogram equalization automatic gain control.
+//
+// It is stateful; feed it successive frames so the temporal damping can
+// prevent flickering. It is not safe for concurrent use.
+type HEQ struct {
+	opts  HEQOpts
+	hist  []int
+	lut   []float32 // Transfer function in [0, 1] per intensity.
+	valid bool
+}
+
+// NewHEQ returns an initialized HEQ. If opts is nil, DefaultHEQOpts is used.
+func NewHEQ(opts *HEQOpts) *HEQ {
+	h := &HEQ{
+		opts: DefaultHEQOpts,
+		hist: make([]int, histSize),
+		lut:  make([]float32, histSize),
+	}
+	if opts != nil {
+		h.opts = *opts
+	}
+	if h.opts.Damping < 0 {
+		h.opts.Damping = 0
+	} else if h.opts.Damping > 256 {
+		h.opts.Damping = 256
+	}
+	if h.opts.LinearPercent < 0 {
+		h.opts.LinearPercent = 0
+	} else if h.opts.LinearPercent > 100 {
+		h.opts.LinearPercent = 100
+	}
+	return h
+}
+
+// Reset forgets the previous transfer function, so the next frame is not
+// damped.
+func (h *HEQ) Reset() {
+	h.valid = false
+}
+
+// Apply reduces the dynamic range of an image down to 8 bits via histogram
+// equalization and updates the transfer function state.
+func (h *HEQ) Apply(i *image14bit.Gray14) *image.Gray {
+	b := i.Bounds()
+	dst := image.NewGray(b)
+	if b.Empty() {
+		return dst
+	}
+	h.update(i)
+	low := float32(h.opts.OutputMin)
+	scale := float32(h.opts.OutputMax) - low
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		base := i.PixOffset(b.Min.X, y)
+		dBase := dst.PixOffset(b.Min.X, y)
+		for x, j := range i.Pix[base : base+b.Dx()] {
+			dst.Pix[dBase+x] = uint8(low + h.lut[j]*scale + 0.5)
+		}
+	}
+	return dst
+}
+
+// Private details.
+
+// histSize is the number of bins to cover all the intensities, including the
+// 16 bits TLinear counts.
+const histSize = 1 << 16
+
+// update recalculates the transfer function from the histogram of i.
+func (h *HEQ) update(i *image14bit.Gray14) {
+	for n := range h.hist {
+		h.hist[n] = 0
+	}
+	b := i.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		base := i.PixOffset(b.Min.X, y)
+		for _, j := range i.Pix[base : base+b.Dx()] {
+			h.hist[j]++
+		}
+	}
+
+	// Find the populated range, ignoring empty bins.
+	lo, hi := -1, -1
+	for n, c := range h.hist {
+		if c > h.opts.EmptyCounts {
+			if lo == -1 {
+				lo = n
+			}
+			hi = n
+		}
+	}
+	if lo == -1 {
+		// Everything is empty; use the raw range instead.
+		for n, c := range h.hist {
+			if c != 0 {
+				if lo == -1 {
+					lo = n
+				}
+				hi = n
+			}
+		}
+	}
+
+	// Clip the histogram and accumulate it.
+	total := 0
+	for n := lo; n <= hi; n++ {
+		c := h.hist[n]
+		if c <= h.opts.EmptyCounts {
+			c = 0
+		} else {
+			c += h.opts.ClipLimitLow
+			if c > h.opts.ClipLimitHigh {
+				c = h.opts.ClipLimitHigh
+			}
+		}
+		total += c
+		h.hist[n] = total
+	}
+
+	linear := float32(h.opts.LinearPercent) / 100
+	damping := float32(h.opts.Damping) / 256
+	if !h.valid {
+		damping = 0
+		h.valid = true
+	}
+	for n := range h.lut {
+		var v float32
+		switch {
+		case n <= lo:
+			v = 0
+		case n >= hi:
+			v = 1
+		default:
+			cdf := float32(0)
+			if total != 0 {
+				cdf = float32(h.hist[n]) / float32(total)
+			}
+			lin := float32(n-lo) / float32(hi-lo)
+			v = (1-linear)*cdf + linear*lin
+		}
+		h.lut[n] = damping*h.lut[n] + (1-damping)*v
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gray14
+
+import (
+	"image"
+	"testing"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+func TestHEQ(t *testing.T) {
+	i := image14bit.NewGray14(image.Rect(0, 0, 80, 60))
+	for n := range i.Pix {
+		i.Pix[n] = uint16(8000 + n%100)
+	}
+	// A single hot pixel must not ruin the output.
+	i.Pix[0] = 16000
+	opts := DefaultHEQOpts
+	opts.Damping = 0
+	h := NewHEQ(&opts)
+	dst := h.Apply(i)
+	if dst.Bounds() != i.Bounds() {
+		t.Fatal(dst.Bounds())
+	}
+	if dst.Pix[1] > 10 {
+		t.Fatal(dst.Pix[1])
+	}
+	if dst.Pix[99] < 250 {
+		t.Fatal(dst.Pix[99])
+	}
+	if dst.Pix[50] < 100 || dst.Pix[50] > 155 {
+		t.Fatal(dst.Pix[50])
+	}
+}
+
+func TestHEQTLinear(t *testing.T) {
+	// TLinear counts use the whole 16 bits; 29315 is 20°C.
+	i := image14bit.NewGray14(image.Rect(0, 0, 80, 60))
+	for n := range i.Pix {
+		i.Pix[n] = uint16(29300 + n%100)
+	}
+	opts := DefaultHEQOpts
+	opts.Damping = 0
+	dst := NewHEQ(&opts).Apply(i)
+	if dst.Pix[1] > 10 || dst.Pix[99] < 250 {
+		t.Fatal(dst.Pix[1], dst.Pix[99])
+	}
+}
+
+func TestHEQDamping(t *testing.T) {
+	a := image14bit.NewGray14(image.Rect(0, 0, 2, 1))
+	a.Pix[0], a.Pix[1] = 100, 200
+	b := image14bit.NewGray14(image.Rect(0, 0, 2, 1))
+	b.Pix[0], b.Pix[1] = 150, 300
+	h := NewHEQ(nil)
+	h.Apply(a)
+	if v := h.Apply(b).Pix[0]; v == 0 {
+		t.Fatal("expected damping to keep some of the previous transfer function")
+	}
+	h.Reset()
+	if v := h.Apply(b).Pix[0]; v != 0 {
+		t.Fatal(v)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gray14
+
+import (
+	"image"
+	"image/color"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// Isotherm is a band of intensities to highlight with a color.
+//
+// Use radiometry.Linear.Count to convert temperatures into intensities.
+type Isotherm struct {
+	// Low and High are inclusive.
+	Low   image14bit.Intensity14
+	High  image14bit.Intensity14
+	Color color.NRGBA
+}
+
+// Isotherms renders the pixels of i that are within a band with the band's
+// color, and the others with base colorized with palette p.
+//
+// base is usually the output of an AGC on i and must have the same bounds.
+// If p is nil, base is rendered in gray. The alpha channel of the band color
+// is used to blend it over the base. When bands overlap, the last one wins.
+func Isotherms(i *image14bit.Gray14, base *image.Gray, p *Palette, bands []Isotherm) *image.NRGBA {
+	if p == nil {
+		p = PaletteByName("white-hot")
+	}
+	dst := p.Colorize(base)
+	b := i.Bounds()
+	if base.Bounds() != b {
+		return dst
+	}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		off := i.PixOffset(b.Min.X, y)
+		dBase := dst.PixOffset(b.Min.X, y)
+		for x, j := range i.Pix[off : off+b.Dx()] {
+			for n := len(bands) - 1; n >= 0; n-- {
+				if band := &bands[n]; image14bit.Intensity14(j) >= band.Low && image14bit.Intensity14(j) <= band.High {
+					blend(dst.Pix[dBase+4*x:dBase+4*x+4], band.Color)
+					break
+				}
+			}
+		}
+	}
+	return dst
+}
+
+// Private details.
+
+// blend blends c over the NRGBA pixel p.
+func blend(p []uint8, c color.NRGBA) {
+	a := int(c.A)
+	p[0] = uint8((int(c.R)*a + int(p[0])*(255-a) + 127) / 255)
+	p[1] = uint8((int(c.G)*a + int(p[1])*(255-a) + 127) / 255)
+	p[2] = uint8((int(c.B)*a + int(p[2])*(255-a) + 127) / 255)
+}
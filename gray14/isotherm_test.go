@@ -0,0 +1,35 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gray14
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+func TestIsotherms(t *testing.T) {
+	i := image14bit.NewGray14(image.Rect(0, 0, 4, 1))
+	i.Pix[0], i.Pix[1], i.Pix[2], i.Pix[3] = 8000, 8100, 8200, 8300
+	red := color.NRGBA{255, 0, 0, 255}
+	blue := color.NRGBA{0, 0, 255, 128}
+	bands := []Isotherm{{8100, 8200, red}, {8200, 8250, blue}}
+	dst := Isotherms(i, AGCLinear(i), nil, bands)
+	if c := dst.NRGBAAt(0, 0); c != (color.NRGBA{0, 0, 0, 255}) {
+		t.Fatal(c)
+	}
+	if c := dst.NRGBAAt(1, 0); c != red {
+		t.Fatal(c)
+	}
+	// The last band wins and is blended over the gray base.
+	if c := dst.NRGBAAt(2, 0); c.R != 85 || c.G != 85 || c.B != 213 {
+		t.Fatal(c)
+	}
+	if c := dst.NRGBAAt(3, 0); c != (color.NRGBA{255, 255, 255, 255}) {
+		t.Fatal(c)
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gray14
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io/ioutil"
+	"sort"
+	"sync"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// Palette is a false color map of 256 entries.
+//
+// It is used to colorize an 8 bits image, usually the output of an AGC.
+type Palette struct {
+	// Name is the name the palette is registered under.
+	Name   string
+	colors [256]color.NRGBA
+}
+
+// NewPalette returns a palette that linearly interpolates between stops,
+// which are evenly spread over the 256 entries.
+//
+// At least two stops are needed.
+func NewPalette(name string, stops []color.NRGBA) (*Palette, error) {
+	if len(stops) < 2 {
+		return nil, errors.New("gray14: a palette needs at least 2 stops")
+	}
+	p := &Palette{Name: name}
+	last := len(stops) - 1
+	for i := range p.colors {
+		// Position of entry i in stop units, in 1/255th.
+		pos := i * last
+		s := pos / 255
+		if s == last {
+			p.colors[i] = stops[last]
+			continue
+		}
+		f := pos % 255
+		a, b := stops[s], stops[s+1]
+		p.colors[i] = color.NRGBA{
+			R: lerp8(a.R, b.R, f),
+			G: lerp8(a.G, b.G, f),
+			B: lerp8(a.B, b.B, f),
+			A: lerp8(a.A, b.A, f),
+		}
+	}
+	return p, nil
+}
+
+// At returns the color for intensity v.
+func (p *Palette) At(v uint8) color.NRGBA {
+	return p.colors[v]
+}
+
+// Colorize converts an 8 bits image into colors.
+func (p *Palette) Colorize(g *image.Gray) *image.NRGBA {
+	b := g.Bounds()
+	dst := image.NewNRGBA(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		base := g.PixOffset(b.Min.X, y)
+		dBase := dst.PixOffset(b.Min.X, y)
+		for x, v := range g.Pix[base : base+b.Dx()] {
+			c := p.colors[v]
+			o := dBase + 4*x
+			dst.Pix[o] = c.R
+			dst.Pix[o+1] = c.G
+			dst.Pix[o+2] = c.B
+			dst.Pix[o+3] = c.A
+		}
+	}
+	return dst
+}
+
+// PseudoColor maps the image to the palette without AGC.
+//
+// The palette covers 512 counts centered around 8192, like ToRGB.
+func (p *Palette) PseudoColor(i *image14bit.Gray14) *image.NRGBA {
+	b := i.Bounds()
+	dst := image.NewNRGBA(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		base := i.PixOffset(b.Min.X, y)
+		for x, j := range i.Pix[base : base+b.Dx()] {
+			v := 0
+			if j > pseudoColorFloor {
+				if v = int(j-pseudoColorFloor) / 2; v > 255 {
+					v = 255
+				}
+			}
+			dst.SetNRGBA(b.Min.X+x, y, p.colors[v])
+		}
+	}
+	return dst
+}
+
+// Strip returns a strip of the colors of the palette.
+func (p *Palette) Strip(vertical bool) *image.NRGBA {
+	x, y := 256, 1
+	if vertical {
+		x, y = y, x
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, x, y))
+	for x, c := range p.colors {
+		dst.Pix[4*x] = c.R
+		dst.Pix[4*x+1] = c.G
+		dst.Pix[4*x+2] = c.B
+		dst.Pix[4*x+3] = c.A
+	}
+	return dst
+}
+
+// MarshalJSON implements json.Marshaler.
+//
+// The format is {"name": "foo", "colors": ["#rrggbb", ...]} with the 256
+// entries.
+func (p *Palette) MarshalJSON() ([]byte, error) {
+	f := paletteFile{Name: p.Name, Colors: make([]string, len(p.colors))}
+	for i, c := range p.colors {
+		f.Colors[i] = fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return json.Marshal(&f)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+//
+// It accepts the format generated by MarshalJSON. The colors are used as
+// stops so a palette file can list only a few of them.
+func (p *Palette) UnmarshalJSON(b []byte) error {
+	f := paletteFile{}
+	if err := json.Unmarshal(b, &f); err != nil {
+		return err
+	}
+	stops := make([]color.NRGBA, len(f.Colors))
+	for i, s := range f.Colors {
+		var c color.NRGBA
+		if n, err := fmt.Sscanf(s, "#%02x%02x%02x", &c.R, &c.G, &c.B); err != nil || n != 3 {
+			return fmt.Errorf("gray14: invalid color %q", s)
+		}
+		c.A = 255
+		stops[i] = c
+	}
+	n, err := NewPalette(f.Name, stops)
+	if err != nil {
+		return err
+	}
+	*p = *n
+	return nil
+}
+
+// LoadPalette loads a palette from a JSON file.
+//
+// See UnmarshalJSON for the format.
+func LoadPalette(path string) (*Palette, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p := &Palette{}
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if p.Name == "" {
+		return nil, fmt.Errorf("%s: palette has no name", path)
+	}
+	return p, nil
+}
+
+// RegisterPalette registers a palette so it can be retrieved by name.
+//
+// Returns an error if a palette with the same name is already registered.
+func RegisterPalette(p *Palette) error {
+	if p.Name == "" {
+		return errors.New("gray14: a palette needs a name")
+	}
+	palettesMu.Lock()
+	defer palettesMu.Unlock()
+	if _, ok := palettes[p.Name]; ok {
+		return fmt.Errorf("gray14: palette %q is already registered", p.Name)
+	}
+	palettes[p.Name] = p
+	return nil
+}
+
+// PaletteByName returns the registered palette with this name or nil.
+func PaletteByName(name string) *Palette {
+	palettesMu.Lock()
+	defer palettesMu.Unlock()
+	return palettes[name]
+}
+
+// PaletteNames returns the sorted names of all registered palettes.
+func PaletteNames() []string {
+	palettesMu.Lock()
+	defer palettesMu.Unlock()
+	out := make([]string, 0, len(palettes))
+	for n := range palettes {
+		out = append(out, n)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// DefaultPalette is the palette used when none is specified.
+var DefaultPalette *Palette
+
+// Private details.
+
+type paletteFile struct {
+	Name   string   `json:"name"`
+	Colors []string `json:"colors"`
+}
+
+var (
+	palettesMu sync.Mutex
+	palettes   = map[string]*Palette{}
+)
+
+// lerp8 interpolates between a and b, f being in 1/255th.
+func lerp8(a, b uint8, f int) uint8 {
+	return uint8((int(a)*(255-f) + int(b)*f + 127) / 255)
+}
+
+func mustRegister(name string, stops ...color.NRGBA) *Palette {
+	p, err := NewPalette(name, stops)
+	if err != nil {
+		panic(err)
+	}
+	if err := RegisterPalette(p); err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func rgb(r, g, b uint8) color.NRGBA {
+	return color.NRGBA{r, g, b, 255}
+}
+
+func init() {
+	stops := make([]color.NRGBA, 256)
+	for i := range stops {
+		stops[i] = rgb(palette[3*i], palette[3*i+1], palette[3*i+2])
+	}
+	DefaultPalette = mustRegister("default", stops...)
+	// Samples ToRGB over the range Palette.PseudoColor covers.
+	for i := range stops {
+		stops[i] = ToRGB(pseudoColorFloor + 2*image14bit.Intensity14(i))
+	}
+	mustRegister("ycbcr", stops...)
+	mustRegister("ironbow",
+		rgb(0, 0, 0), rgb(32, 0, 140), rgb(204, 0, 119), rgb(255, 165, 0),
+		rgb(255, 255, 255))
+	mustRegister("rainbow",
+		rgb(0, 0, 255), rgb(0, 255, 255), rgb(0, 255, 0), rgb(255, 255, 0),
+		rgb(255, 0, 0))
+	mustRegister("white-hot", rgb(0, 0, 0), rgb(255, 255, 255))
+	mustRegister("black-hot", rgb(255, 255, 255), rgb(0, 0, 0))
+	mustRegister("lava",
+		rgb(0, 0, 0), rgb(20, 20, 90), rgb(190, 20, 20), rgb(255, 130, 0),
+		rgb(255, 230, 60), rgb(255, 255, 255))
+	mustRegister("arctic",
+		rgb(0, 0, 32), rgb(0, 64, 160), rgb(40, 160, 220), rgb(230, 180, 60),
+		rgb(255, 230, 160), rgb(255, 255, 255))
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gray14
+
+import (
+	"encoding/json"
+	"image"
+	"image/color"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+func TestNewPalette(t *testing.T) {
+	p, err := NewPalette("test", []color.NRGBA{{0, 0, 0, 255}, {254, 100, 0, 255}, {255, 255, 255, 255}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := p.At(0); c != (color.NRGBA{0, 0, 0, 255}) {
+		t.Fatal(c)
+	}
+	if c := p.At(127); c.R < 250 || c.G < 95 || c.G > 105 {
+		t.Fatal(c)
+	}
+	if c := p.At(255); c != (color.NRGBA{255, 255, 255, 255}) {
+		t.Fatal(c)
+	}
+	if _, err := NewPalette("test", nil); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestPaletteRegistry(t *testing.T) {
+	for _, n := range []string{"default", "ironbow", "rainbow", "white-hot", "black-hot", "lava", "arctic"} {
+		if PaletteByName(n) == nil {
+			t.Fatal(n)
+		}
+	}
+	if PaletteByName("white-hot").At(255) != (color.NRGBA{255, 255, 255, 255}) {
+		t.Fatal("white-hot")
+	}
+	if err := RegisterPalette(DefaultPalette); err == nil {
+		t.Fatal("expected duplicate error")
+	}
+	if s := PaletteRGB(true); s.Bounds().Dy() != 256 || s.Pix[0] != palette[0] {
+		t.Fatal("unexpected PaletteRGB")
+	}
+	if s := PaletteByName("white-hot").Strip(false); s.Bounds().Dx() != 256 || s.Pix[4*255] != 255 {
+		t.Fatal("unexpected Strip")
+	}
+}
+
+func TestPalettePseudoColor(t *testing.T) {
+	i := image14bit.NewGray14(image.Rect(0, 0, 3, 1))
+	i.Pix[0] = 0
+	i.Pix[1] = 8192
+	i.Pix[2] = 0xFFFF
+	p := PaletteByName("white-hot")
+	dst := p.PseudoColor(i)
+	if dst.NRGBAAt(0, 0) != p.At(0) || dst.NRGBAAt(1, 0) != p.At(128) || dst.NRGBAAt(2, 0) != p.At(255) {
+		t.Fatal(dst.Pix)
+	}
+}
+
+func TestLoadPalette(t *testing.T) {
+	d, err := ioutil.TempDir("", "gray14")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(d)
+	path := filepath.Join(d, "p.json")
+	if err := ioutil.WriteFile(path, []byte(`{"name": "custom", "colors": ["#000000", "#ff0000"]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	p, err := LoadPalette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "custom" || p.At(255) != (color.NRGBA{255, 0, 0, 255}) {
+		t.Fatal(p.Name, p.At(255))
+	}
+	// Round trip.
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := &Palette{}
+	if err := json.Unmarshal(b, q); err != nil {
+		t.Fatal(err)
+	}
+	if *q != *p {
+		t.Fatal("round trip mismatch")
+	}
+}
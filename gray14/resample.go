@@ -0,0 +1,233 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gray14
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// Filter is a resampling filter.
+type Filter int
+
+// Supported resampling filters.
+const (
+	NearestNeighbor Filter = iota
+	Bilinear
+	Bicubic
+	Lanczos
+)
+
+func (f Filter) String() string {
+	switch f {
+	case NearestNeighbor:
+		return "nearest"
+	case Bilinear:
+		return "bilinear"
+	case Bicubic:
+		return "bicubic"
+	case Lanczos:
+		return "lanczos"
+	default:
+		return fmt.Sprintf("Filter(%d)", int(f))
+	}
+}
+
+// ParseFilter returns the Filter for the name returned by Filter.String().
+func ParseFilter(s string) (Filter, error) {
+	for f := NearestNeighbor; f <= Lanczos; f++ {
+		if f.String() == s {
+			return f, nil
+		}
+	}
+	return 0, fmt.Errorf("gray14: unknown filter %q", s)
+}
+
+// Resize resamples an image to w x h. The whole 16 bits range is kept, e.g.
+// for TLinear counts.
+//
+// It is meant to be used on the raw data before AGC so no precision is lost.
+func Resize(i *image14bit.Gray14, w, h int, f Filter) *image14bit.Gray14 {
+	b := i.Bounds()
+	src := make([]float32, b.Dx()*b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		base := i.PixOffset(b.Min.X, y)
+		for x, j := range i.Pix[base : base+b.Dx()] {
+			src[(y-b.Min.Y)*b.Dx()+x] = float32(j)
+		}
+	}
+	out := resample(src, b.Dx(), b.Dy(), 1, w, h, f)
+	dst := image14bit.NewGray14(image.Rect(0, 0, w, h))
+	for n, v := range out {
+		dst.Pix[n] = uint16(clampf(v, math.MaxUint16))
+	}
+	return dst
+}
+
+// ResizeRGB resamples a rendered image to w x h.
+func ResizeRGB(i *image.NRGBA, w, h int, f Filter) *image.NRGBA {
+	b := i.Bounds()
+	src := make([]float32, 4*b.Dx()*b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		base := i.PixOffset(b.Min.X, y)
+		for x, v := range i.Pix[base : base+4*b.Dx()] {
+			src[4*(y-b.Min.Y)*b.Dx()+x] = float32(v)
+		}
+	}
+	out := resample(src, b.Dx(), b.Dy(), 4, w, h, f)
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for n, v := range out {
+		dst.Pix[n] = uint8(clampf(v, 255))
+	}
+	return dst
+}
+
+// Private details.
+
+// kernel returns the support radius and the weight function of a filter.
+func (f Filter) kernel() (float64, func(x float64) float64) {
+	switch f {
+	case Bilinear:
+		return 1, func(x float64) float64 {
+			return 1 - math.Abs(x)
+		}
+	case Bicubic:
+		// Catmull-Rom, a = -0.5.
+		return 2, func(x float64) float64 {
+			x = math.Abs(x)
+			if x < 1 {
+				return (1.5*x-2.5)*x*x + 1
+			}
+			return ((-0.5*x+2.5)*x-4)*x + 2
+		}
+	case Lanczos:
+		// Lanczos3.
+		return 3, func(x float64) float64 {
+			if x == 0 {
+				return 1
+			}
+			px := math.Pi * x
+			return 3 * math.Sin(px) * math.Sin(px/3) / (px * px)
+		}
+	default:
+		return 0.5, func(x float64) float64 {
+			return 1
+		}
+	}
+}
+
+// contrib is the contribution of source pixels to one destination pixel.
+type contrib struct {
+	first   int
+	weights []float32
+}
+
+// contribs calculates the contributions for resampling a line of src pixels
+// into dst pixels.
+func contribs(src, dst int, f Filter) []contrib {
+	support, fn := f.kernel()
+	scale := float64(dst) / float64(src)
+	// When downscaling, widen the kernel to avoid aliasing.
+	stretch := 1.
+	if scale < 1 {
+		stretch = 1 / scale
+	}
+	out := make([]contrib, dst)
+	for i := range out {
+		center := (float64(i)+0.5)/scale - 0.5
+		if f == NearestNeighbor {
+			j := int(math.Floor(center + 0.5))
+			if j >= src {
+				j = src - 1
+			}
+			out[i] = contrib{first: j, weights: []float32{1}}
+			continue
+		}
+		r := support * stretch
+		first := int(math.Ceil(center - r))
+		last := int(math.Floor(center + r))
+		w := make([]float64, last-first+1)
+		sum := 0.
+		for j := range w {
+			w[j] = fn((float64(first+j) - center) / stretch)
+			sum += w[j]
+		}
+		c := contrib{first: first, weights: make([]float32, len(w))}
+		for j := range w {
+			c.weights[j] = float32(w[j] / sum)
+		}
+		out[i] = c
+	}
+	return out
+}
+
+// resample resizes an interleaved plane of ch channels from sw x sh to
+// dw x dh, clamping at the edges.
+func resample(src []float32, sw, sh, ch, dw, dh int, f Filter) []float32 {
+	if sw == 0 || sh == 0 || dw <= 0 || dh <= 0 {
+		return make([]float32, ch*max(dw, 0)*max(dh, 0))
+	}
+	// Horizontal pass.
+	tmp := make([]float32, ch*dw*sh)
+	cx := contribs(sw, dw, f)
+	for y := 0; y < sh; y++ {
+		row := src[ch*y*sw : ch*(y+1)*sw]
+		for x, c := range cx {
+			for k := 0; k < ch; k++ {
+				var v float32
+				for j, w := range c.weights {
+					v += w * row[ch*clampi(c.first+j, sw)+k]
+				}
+				tmp[ch*(y*dw+x)+k] = v
+			}
+		}
+	}
+	// Vertical pass.
+	dst := make([]float32, ch*dw*dh)
+	cy := contribs(sh, dh, f)
+	for y, c := range cy {
+		for x := 0; x < ch*dw; x++ {
+			var v float32
+			for j, w := range c.weights {
+				v += w * tmp[ch*dw*clampi(c.first+j, sh)+x]
+			}
+			dst[ch*dw*y+x] = v
+		}
+	}
+	return dst
+}
+
+// clampi clamps i to [0, n).
+func clampi(i, n int) int {
+	if i < 0 {
+		return 0
+	}
+	if i >= n {
+		return n - 1
+	}
+	return i
+}
+
+// clampf rounds v and clamps it to [0, m].
+func clampf(v float32, m int) int {
+	i := int(v + 0.5)
+	if v < 0 || i < 0 {
+		return 0
+	}
+	if i > m {
+		return m
+	}
+	return i
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
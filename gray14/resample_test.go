@@ -0,0 +1,75 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gray14
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+func TestResize(t *testing.T) {
+	i := image14bit.NewGray14(image.Rect(0, 0, 80, 60))
+	for y := 0; y < 60; y++ {
+		for x := 0; x < 80; x++ {
+			i.SetIntensity14(x, y, image14bit.Intensity14(8000+x))
+		}
+	}
+	for f := NearestNeighbor; f <= Lanczos; f++ {
+		dst := Resize(i, 800, 600, f)
+		if dst.Bounds() != image.Rect(0, 0, 800, 600) {
+			t.Fatal(f, dst.Bounds())
+		}
+		if v := dst.Intensity14At(0, 0); v != 8000 {
+			t.Fatal(f, v)
+		}
+		if v := dst.Intensity14At(799, 599); v != 8079 {
+			t.Fatal(f, v)
+		}
+		if f != NearestNeighbor {
+			// A smooth gradient stays smooth.
+			for x := 1; x < 800; x++ {
+				if d := int(dst.Intensity14At(x, 300)) - int(dst.Intensity14At(x-1, 300)); d < 0 || d > 1 {
+					t.Fatal(f, x, d)
+				}
+			}
+		}
+		if s, err := ParseFilter(f.String()); err != nil || s != f {
+			t.Fatal(f, s, err)
+		}
+	}
+	// Downscale.
+	if dst := Resize(i, 40, 30, Lanczos); dst.Intensity14At(20, 15) < 8039 || dst.Intensity14At(20, 15) > 8042 {
+		t.Fatal(dst.Intensity14At(20, 15))
+	}
+}
+
+func TestResizeTLinear(t *testing.T) {
+	// TLinear counts use the whole 16 bits; 29315 is 20°C.
+	i := image14bit.NewGray14(image.Rect(0, 0, 2, 2))
+	for n := range i.Pix {
+		i.Pix[n] = 29315
+	}
+	for f := NearestNeighbor; f <= Lanczos; f++ {
+		if v := Resize(i, 8, 8, f).Intensity14At(5, 5); v != 29315 {
+			t.Fatal(f, v)
+		}
+	}
+}
+
+func TestResizeRGB(t *testing.T) {
+	i := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 2; x++ {
+			i.SetNRGBA(x, y, color.NRGBA{255, 0, 0, 255})
+		}
+	}
+	dst := ResizeRGB(i, 7, 5, Bicubic)
+	if c := dst.NRGBAAt(3, 2); c != (color.NRGBA{255, 0, 0, 255}) {
+		t.Fatal(c)
+	}
+}
@@ -0,0 +1,280 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gray14
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// ROI is a region of interest in an image.
+type ROI interface {
+	// Bounds returns the smallest rectangle containing all the pixels of the
+	// region.
+	Bounds() image.Rectangle
+	// Contains returns true if the pixel p is part of the region.
+	Contains(p image.Point) bool
+	// Each calls fn once for each pixel of the region.
+	Each(fn func(p image.Point))
+}
+
+// ROIStats calculates the statistics over the pixels of the image inside r.
+// If opts is nil, DefaultStatsOpts is used.
+func ROIStats(i *image14bit.Gray14, r ROI, opts *StatsOpts) *Statistics {
+	b := i.Bounds()
+	a := newStatsAcc(opts, 0)
+	r.Each(func(p image.Point) {
+		if p.In(b) {
+			a.add(p.X, p.Y, image14bit.Intensity14(i.Pix[i.PixOffset(p.X, p.Y)]))
+		}
+	})
+	return a.finish()
+}
+
+// Rect is a rectangular region. Max is exclusive, like image.Rectangle.
+type Rect image.Rectangle
+
+// Bounds implements ROI.
+func (r Rect) Bounds() image.Rectangle {
+	return image.Rectangle(r).Canon()
+}
+
+// Contains implements ROI.
+func (r Rect) Contains(p image.Point) bool {
+	return p.In(r.Bounds())
+}
+
+// Each implements ROI.
+func (r Rect) Each(fn func(p image.Point)) {
+	eachContained(r, fn)
+}
+
+// Ellipse is an axis aligned elliptic region.
+type Ellipse struct {
+	Center image.Point
+	// RX and RY are the radius on each axis.
+	RX int
+	RY int
+}
+
+// Bounds implements ROI.
+func (e *Ellipse) Bounds() image.Rectangle {
+	return image.Rect(e.Center.X-e.RX, e.Center.Y-e.RY, e.Center.X+e.RX+1, e.Center.Y+e.RY+1)
+}
+
+// Contains implements ROI.
+func (e *Ellipse) Contains(p image.Point) bool {
+	if e.RX <= 0 || e.RY <= 0 {
+		return p == e.Center
+	}
+	dx := float64(p.X-e.Center.X) / float64(e.RX)
+	dy := float64(p.Y-e.Center.Y) / float64(e.RY)
+	return dx*dx+dy*dy <= 1
+}
+
+// Each implements ROI.
+func (e *Ellipse) Each(fn func(p image.Point)) {
+	eachContained(e, fn)
+}
+
+// Polygon is a closed polygonal region. The last point is connected to the
+// first one.
+type Polygon struct {
+	Points []image.Point
+}
+
+// Bounds implements ROI.
+func (g *Polygon) Bounds() image.Rectangle {
+	if len(g.Points) == 0 {
+		return image.Rectangle{}
+	}
+	r := image.Rectangle{g.Points[0], g.Points[0].Add(image.Point{1, 1})}
+	for _, p := range g.Points[1:] {
+		r = r.Union(image.Rectangle{p, p.Add(image.Point{1, 1})})
+	}
+	return r
+}
+
+// Contains implements ROI.
+//
+// It uses the even-odd rule. Pixels on the edges are included.
+func (g *Polygon) Contains(p image.Point) bool {
+	n := len(g.Points)
+	inside := false
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		a, b := g.Points[i], g.Points[j]
+		if onSegment(a, b, p) {
+			return true
+		}
+		if (a.Y > p.Y) != (b.Y > p.Y) {
+			x := float64(b.X-a.X)*float64(p.Y-a.Y)/float64(b.Y-a.Y) + float64(a.X)
+			if float64(p.X) < x {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// Each implements ROI.
+func (g *Polygon) Each(fn func(p image.Point)) {
+	eachContained(g, fn)
+}
+
+// Line is a one pixel wide segment between A and B inclusively.
+type Line struct {
+	A image.Point
+	B image.Point
+}
+
+// Bounds implements ROI.
+func (l *Line) Bounds() image.Rectangle {
+	return image.Rectangle{l.A, l.A.Add(image.Point{1, 1})}.Union(image.Rectangle{l.B, l.B.Add(image.Point{1, 1})})
+}
+
+// Contains implements ROI.
+func (l *Line) Contains(p image.Point) bool {
+	if !p.In(l.Bounds()) {
+		return false
+	}
+	found := false
+	l.Each(func(q image.Point) {
+		found = found || q == p
+	})
+	return found
+}
+
+// Each implements ROI.
+//
+// Pixels are visited from A to B using Bresenham's algorithm.
+func (l *Line) Each(fn func(p image.Point)) {
+	dx, dy := abs(l.B.X-l.A.X), -abs(l.B.Y-l.A.Y)
+	sx, sy := 1, 1
+	if l.A.X > l.B.X {
+		sx = -1
+	}
+	if l.A.Y > l.B.Y {
+		sy = -1
+	}
+	e := dx + dy
+	p := l.A
+	for {
+		fn(p)
+		if p == l.B {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			p.X += sx
+		}
+		if e2 <= dx {
+			e += dx
+			p.Y += sy
+		}
+	}
+}
+
+// Region is a named ROI that can be serialized to JSON.
+//
+// The JSON format is {"Name": "foo", "Type": "rect", "Shape": {...}} where
+// Type is one of "rect", "ellipse", "polygon" or "line" and Shape is the
+// JSON encoding of the corresponding type.
+type Region struct {
+	Name string
+	ROI  ROI
+}
+
+// MarshalJSON implements json.Marshaler.
+func (r Region) MarshalJSON() ([]byte, error) {
+	var t string
+	switch r.ROI.(type) {
+	case Rect:
+		t = "rect"
+	case *Ellipse:
+		t = "ellipse"
+	case *Polygon:
+		t = "polygon"
+	case *Line:
+		t = "line"
+	default:
+		return nil, fmt.Errorf("gray14: unsupported ROI type %T", r.ROI)
+	}
+	s, err := json.Marshal(r.ROI)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&regionJSON{Name: r.Name, Type: t, Shape: s})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (r *Region) UnmarshalJSON(b []byte) error {
+	j := regionJSON{}
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	var err error
+	switch j.Type {
+	case "rect":
+		v := Rect{}
+		err = json.Unmarshal(j.Shape, &v)
+		r.ROI = v
+	case "ellipse":
+		v := &Ellipse{}
+		err = json.Unmarshal(j.Shape, v)
+		r.ROI = v
+	case "polygon":
+		v := &Polygon{}
+		err = json.Unmarshal(j.Shape, v)
+		r.ROI = v
+	case "line":
+		v := &Line{}
+		err = json.Unmarshal(j.Shape, v)
+		r.ROI = v
+	default:
+		return fmt.Errorf("gray14: unknown ROI type %q", j.Type)
+	}
+	r.Name = j.Name
+	return err
+}
+
+// Private details.
+
+type regionJSON struct {
+	Name  string
+	Type  string
+	Shape json.RawMessage
+}
+
+// eachContained calls fn for each pixel within r.Bounds() for which
+// r.Contains() is true.
+func eachContained(r ROI, fn func(p image.Point)) {
+	b := r.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if p := (image.Point{x, y}); r.Contains(p) {
+				fn(p)
+			}
+		}
+	}
+}
+
+// onSegment returns true if p is on the segment [a, b].
+func onSegment(a, b, p image.Point) bool {
+	if (b.X-a.X)*(p.Y-a.Y) != (b.Y-a.Y)*(p.X-a.X) {
+		return false
+	}
+	return p.In(image.Rectangle{a, a.Add(image.Point{1, 1})}.Union(image.Rectangle{b, b.Add(image.Point{1, 1})}))
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
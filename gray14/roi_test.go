@@ -0,0 +1,99 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gray14
+
+import (
+	"encoding/json"
+	"image"
+	"reflect"
+	"testing"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+func count(r ROI) int {
+	n := 0
+	r.Each(func(p image.Point) {
+		if !r.Contains(p) {
+			panic(p)
+		}
+		n++
+	})
+	return n
+}
+
+func TestROIShapes(t *testing.T) {
+	data := []struct {
+		r ROI
+		n int
+	}{
+		{Rect(image.Rect(1, 1, 4, 3)), 6},
+		{&Ellipse{Center: image.Point{5, 5}, RX: 2, RY: 1}, 7},
+		{&Polygon{Points: []image.Point{{0, 0}, {4, 0}, {4, 4}, {0, 4}}}, 25},
+		{&Line{A: image.Point{0, 0}, B: image.Point{5, 2}}, 6},
+		{&Line{A: image.Point{3, 3}, B: image.Point{3, 0}}, 4},
+	}
+	for i, line := range data {
+		if n := count(line.r); n != line.n {
+			t.Fatalf("#%d: %d != %d", i, n, line.n)
+		}
+	}
+	if (&Ellipse{Center: image.Point{5, 5}, RX: 2, RY: 1}).Contains(image.Point{7, 6}) {
+		t.Fatal("corner is outside the ellipse")
+	}
+}
+
+func TestROIStats(t *testing.T) {
+	i := image14bit.NewGray14(image.Rect(0, 0, 10, 10))
+	for n := range i.Pix {
+		i.Pix[n] = 1000
+	}
+	i.SetIntensity14(2, 2, 2000)
+	i.SetIntensity14(8, 8, 3000)
+	s := ROIStats(i, Rect(image.Rect(0, 0, 5, 5)), nil)
+	if s.Count != 25 || s.Max != 2000 || s.MaxPos != (image.Point{2, 2}) {
+		t.Fatal(s.Count, s.Max, s.MaxPos)
+	}
+	// Partially outside the image.
+	s = ROIStats(i, Rect(image.Rect(8, 8, 20, 20)), nil)
+	if s.Count != 4 || s.Max != 3000 {
+		t.Fatal(s.Count, s.Max)
+	}
+}
+
+func TestRegionJSON(t *testing.T) {
+	regions := []Region{
+		{"box", Rect(image.Rect(1, 2, 3, 4))},
+		{"window", &Ellipse{image.Point{5, 5}, 2, 3}},
+		{"poly", &Polygon{[]image.Point{{0, 0}, {3, 0}, {0, 3}}}},
+		{"line", &Line{image.Point{0, 0}, image.Point{3, 3}}},
+	}
+	b, err := json.Marshal(regions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []Region
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(regions, got) {
+		t.Fatalf("%s\n%#v", b, got)
+	}
+	// Values that are not addressable, like map values, must round trip too.
+	m := map[string]Region{"box": regions[0]}
+	if b, err = json.Marshal(m); err != nil {
+		t.Fatal(err)
+	}
+	var gotm map[string]Region
+	if err := json.Unmarshal(b, &gotm); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m, gotm) {
+		t.Fatalf("%s\n%#v", b, gotm)
+	}
+	if err := json.Unmarshal([]byte(`{"Name":"x","Type":"star"}`), &Region{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
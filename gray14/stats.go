@@ -0,0 +1,143 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gray14
+
+import (
+	"image"
+	"math"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// DefaultDeadThreshold is the intensity under which a pixel is considered
+// dead and is ignored by Min and by default by Stats.
+const DefaultDeadThreshold = 100
+
+// StatsOpts are the parameters for Stats.
+type StatsOpts struct {
+	// DeadThreshold is the intensity under which a pixel is considered dead
+	// and is ignored. 0 means no pixel is ignored.
+	DeadThreshold image14bit.Intensity14
+	// Percentiles are the percentiles to calculate, each in [0, 100].
+	Percentiles []float64
+}
+
+// DefaultStatsOpts ignores dead pixels like Min and calculates the median.
+var DefaultStatsOpts = StatsOpts{
+	DeadThreshold: DefaultDeadThreshold,
+	Percentiles:   []float64{50},
+}
+
+// Statistics are the statistics of the intensity of a set of pixels.
+type Statistics struct {
+	// Count is the number of pixels considered, excluding dead pixels.
+	Count  int
+	Min    image14bit.Intensity14
+	MinPos image.Point
+	Max    image14bit.Intensity14
+	MaxPos image.Point
+	Mean   float64
+	StdDev float64
+	// Histogram is the population of each intensity from Min to Max included;
+	// Histogram[v-Min] is the population of v.
+	Histogram []int `json:"-"`
+	// Percentiles are the values for StatsOpts.Percentiles, in the same
+	// order.
+	Percentiles []image14bit.Intensity14
+}
+
+// Stats calculates the statistics of the image in a single pass over the
+// pixels. If opts is nil, DefaultStatsOpts is used.
+//
+// If no pixel is considered, Count is 0 and the other values are zero.
+func Stats(i *image14bit.Gray14, opts *StatsOpts) *Statistics {
+	b := i.Bounds()
+	a := newStatsAcc(opts, b.Dx()*b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		base := i.PixOffset(b.Min.X, y)
+		for x, j := range i.Pix[base : base+b.Dx()] {
+			a.add(b.Min.X+x, y, image14bit.Intensity14(j))
+		}
+	}
+	return a.finish()
+}
+
+// Private details.
+
+// statsAcc accumulates pixels to calculate Statistics.
+type statsAcc struct {
+	opts   StatsOpts
+	s      Statistics
+	sum    float64
+	sumSq  float64
+	values []image14bit.Intensity14 // To build the histogram once Min and Max are known.
+}
+
+// newStatsAcc returns a statsAcc expecting about n pixels.
+func newStatsAcc(opts *StatsOpts, n int) *statsAcc {
+	a := &statsAcc{opts: DefaultStatsOpts, values: make([]image14bit.Intensity14, 0, n)}
+	if opts != nil {
+		a.opts = *opts
+	}
+	return a
+}
+
+func (a *statsAcc) add(x, y int, j image14bit.Intensity14) {
+	if j < a.opts.DeadThreshold {
+		return
+	}
+	if a.s.Count == 0 || j < a.s.Min {
+		a.s.Min = j
+		a.s.MinPos = image.Point{x, y}
+	}
+	if a.s.Count == 0 || j > a.s.Max {
+		a.s.Max = j
+		a.s.MaxPos = image.Point{x, y}
+	}
+	a.s.Count++
+	a.values = append(a.values, j)
+	f := float64(j)
+	a.sum += f
+	a.sumSq += f * f
+}
+
+func (a *statsAcc) finish() *Statistics {
+	s := a.s
+	s.Percentiles = make([]image14bit.Intensity14, len(a.opts.Percentiles))
+	if s.Count == 0 {
+		return &s
+	}
+	s.Histogram = make([]int, int(s.Max-s.Min)+1)
+	for _, j := range a.values {
+		s.Histogram[j-s.Min]++
+	}
+	n := float64(s.Count)
+	s.Mean = a.sum / n
+	if v := a.sumSq/n - s.Mean*s.Mean; v > 0 {
+		s.StdDev = math.Sqrt(v)
+	}
+	for i, p := range a.opts.Percentiles {
+		s.Percentiles[i] = s.Min + percentile(s.Histogram, s.Count, p)
+	}
+	return &s
+}
+
+// percentile returns the lowest bin for which at least p percent of the
+// pixels are lower or equal, using the nearest rank method.
+func percentile(hist []int, count int, p float64) image14bit.Intensity14 {
+	rank := int(math.Ceil(p / 100 * float64(count)))
+	if rank < 1 {
+		rank = 1
+	} else if rank > count {
+		rank = count
+	}
+	sum := 0
+	for v, c := range hist {
+		if sum += c; sum >= rank {
+			return image14bit.Intensity14(v)
+		}
+	}
+	return image14bit.Intensity14(len(hist) - 1)
+}
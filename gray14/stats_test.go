@@ -0,0 +1,73 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gray14
+
+import (
+	"image"
+	"testing"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+func TestStats(t *testing.T) {
+	i := image14bit.NewGray14(image.Rect(0, 0, 10, 10))
+	for n := range i.Pix {
+		i.Pix[n] = uint16(1000 + n)
+	}
+	i.SetIntensity14(3, 4, 50)
+	opts := StatsOpts{DeadThreshold: 100, Percentiles: []float64{0, 50, 90, 100}}
+	s := Stats(i, &opts)
+	if s.Count != 99 {
+		t.Fatal(s.Count)
+	}
+	if s.Min != 1000 || s.MinPos != (image.Point{0, 0}) {
+		t.Fatal(s.Min, s.MinPos)
+	}
+	if s.Max != 1099 || s.MaxPos != (image.Point{9, 9}) {
+		t.Fatal(s.Max, s.MaxPos)
+	}
+	if len(s.Histogram) != 100 || s.Histogram[42] != 1 || s.Histogram[43] != 0 {
+		t.Fatal("unexpected histogram")
+	}
+	if s.Mean < 1049 || s.Mean > 1050 {
+		t.Fatal(s.Mean)
+	}
+	if s.StdDev < 28 || s.StdDev > 30 {
+		t.Fatal(s.StdDev)
+	}
+	if s.Percentiles[0] != 1000 || s.Percentiles[1] != 1050 || s.Percentiles[2] != 1090 || s.Percentiles[3] != 1099 {
+		t.Fatal(s.Percentiles)
+	}
+
+	// Without the threshold, the dead pixel is the minimum.
+	s = Stats(i, &StatsOpts{})
+	if s.Count != 100 || s.Min != 50 || s.MinPos != (image.Point{3, 4}) {
+		t.Fatal(s.Count, s.Min, s.MinPos)
+	}
+}
+
+func TestStatsTLinear(t *testing.T) {
+	// TLinear counts use the whole 16 bits; 29315 is 20°C.
+	i := image14bit.NewGray14(image.Rect(0, 0, 10, 10))
+	for n := range i.Pix {
+		i.Pix[n] = uint16(29300 + n)
+	}
+	i.Pix[99] = 0xFFFF
+	s := Stats(i, &StatsOpts{Percentiles: []float64{0, 50, 100}})
+	if s.Min != 29300 || s.Max != 0xFFFF || len(s.Histogram) != 0xFFFF-29300+1 {
+		t.Fatal(s.Min, s.Max, len(s.Histogram))
+	}
+	if s.Percentiles[0] != 29300 || s.Percentiles[1] != 29349 || s.Percentiles[2] != 0xFFFF {
+		t.Fatal(s.Percentiles)
+	}
+}
+
+func TestStatsEmpty(t *testing.T) {
+	i := image14bit.NewGray14(image.Rect(0, 0, 2, 2))
+	s := Stats(i, nil)
+	if s.Count != 0 || s.Min != 0 || s.Max != 0 || len(s.Percentiles) != 1 {
+		t.Fatal(s)
+	}
+}
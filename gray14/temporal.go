@@ -0,0 +1,99 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gray14
+
+import (
+	"image"
+	"math"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// TemporalOpts are the parameters for Temporal.
+type TemporalOpts struct {
+	// Alpha is the weight [0, 1] of a new frame on a still pixel. Lower
+	// values reduce the noise more but take longer to converge.
+	Alpha float64
+	// MotionThreshold is the difference in counts between a new pixel and its
+	// estimate at which the pixel is considered to be moving. A moving pixel
+	// takes the new value as-is so moving objects do not smear. The weight is
+	// increased progressively from Alpha up to this threshold.
+	MotionThreshold int
+}
+
+// DefaultTemporalOpts are defaults suitable for a Lepton running at ~9Hz.
+var DefaultTemporalOpts = TemporalOpts{
+	Alpha:           0.25,
+	MotionThreshold: 32,
+}
+
+// Temporal is a recursive temporal noise reduction filter.
+//
+// It keeps a running estimate of each pixel. It is not safe for concurrent
+// use.
+type Temporal struct {
+	opts   TemporalOpts
+	bounds image.Rectangle
+	est    []float32
+}
+
+// NewTemporal returns an initialized Temporal. If opts is nil,
+// DefaultTemporalOpts is used.
+func NewTemporal(opts *TemporalOpts) *Temporal {
+	t := &Temporal{opts: DefaultTemporalOpts}
+	if opts != nil {
+		t.opts = *opts
+	}
+	if t.opts.Alpha <= 0 || t.opts.Alpha > 1 {
+		t.opts.Alpha = 1
+	}
+	if t.opts.MotionThreshold < 1 {
+		t.opts.MotionThreshold = 1
+	}
+	return t
+}
+
+// Reset forgets the running estimate.
+func (t *Temporal) Reset() {
+	t.est = nil
+}
+
+// Filter blends i into the running estimate and replaces the pixels of i
+// with the filtered values.
+//
+// The estimate is reset when the bounds of the image change.
+func (t *Temporal) Filter(i *image14bit.Gray14) {
+	b := i.Bounds()
+	if t.est == nil || b != t.bounds {
+		t.bounds = b
+		t.est = make([]float32, b.Dx()*b.Dy())
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			base := i.PixOffset(b.Min.X, y)
+			for x, j := range i.Pix[base : base+b.Dx()] {
+				t.est[(y-b.Min.Y)*b.Dx()+x] = float32(j)
+			}
+		}
+		return
+	}
+	alpha := float32(t.opts.Alpha)
+	threshold := float32(t.opts.MotionThreshold)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		base := i.PixOffset(b.Min.X, y)
+		est := t.est[(y-b.Min.Y)*b.Dx() : (y-b.Min.Y+1)*b.Dx()]
+		for x, j := range i.Pix[base : base+b.Dx()] {
+			v := float32(j)
+			d := v - est[x]
+			if d < 0 {
+				d = -d
+			}
+			a := float32(1)
+			if d < threshold {
+				a = alpha + (1-alpha)*d/threshold
+			}
+			est[x] += a * (v - est[x])
+			i.Pix[base+x] = uint16(clampf(est[x], math.MaxUint16))
+		}
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gray14
+
+import (
+	"image"
+	"testing"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+func TestTemporal(t *testing.T) {
+	f := NewTemporal(nil)
+	frame := func(still, moving uint16) *image14bit.Gray14 {
+		i := image14bit.NewGray14(image.Rect(0, 0, 2, 1))
+		i.Pix[0] = still
+		i.Pix[1] = moving
+		return i
+	}
+	i := frame(8000, 8000)
+	f.Filter(i)
+	if i.Pix[0] != 8000 || i.Pix[1] != 8000 {
+		t.Fatal(i.Pix)
+	}
+	// Noise on the still pixel is dampened, a person walking in is not.
+	i = frame(8008, 9000)
+	f.Filter(i)
+	if i.Pix[0] <= 8000 || i.Pix[0] >= 8006 {
+		t.Fatal(i.Pix[0])
+	}
+	if i.Pix[1] != 9000 {
+		t.Fatal(i.Pix[1])
+	}
+	// TLinear counts use the whole 16 bits; 29315 is 20°C.
+	i = frame(29315, 0xFFFF)
+	f.Filter(i)
+	if i.Pix[0] != 29315 || i.Pix[1] != 0xFFFF {
+		t.Fatal(i.Pix)
+	}
+	// Bounds change resets.
+	i = image14bit.NewGray14(image.Rect(0, 0, 1, 1))
+	i.Pix[0] = 100
+	f.Filter(i)
+	if i.Pix[0] != 100 {
+		t.Fatal(i.Pix[0])
+	}
+}
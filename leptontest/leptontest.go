@@ -46,6 +46,7 @@ func New() (*LeptonFake, error) {
 func (l *LeptonFake) NextFrame(img *lepton.Frame) error {
 	// ~9hz
 	time.Sleep(111 * time.Millisecond)
+	img.Metadata.SinceStartup = time.Since(l.start)
 	img.Metadata.FrameCount = l.last.Metadata.FrameCount + 1
 	img.Metadata.Temp = physic.ZeroCelsius
 	l.noise.update()
@@ -0,0 +1,79 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package leptontest
+
+import (
+	"image"
+	"math/rand"
+	"time"
+
+	"periph.io/x/periph/devices/lepton"
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// Object is a warm rectangle in a Scene, like a person seen from afar.
+type Object struct {
+	Bounds    image.Rectangle
+	Intensity image14bit.Intensity14
+}
+
+// Scene renders synthetic frames, to test the processing of the frame stream
+// in a deterministic way.
+type Scene struct {
+	// Background is the intensity of the empty scene.
+	Background image14bit.Intensity14
+	// Noise is the standard deviation of the noise added to every pixel, in
+	// counts.
+	Noise float64
+	// Objects are drawn in order over the background.
+	Objects []Object
+	// Rate is the frame rate. Defaults to 9Hz.
+	Rate time.Duration
+
+	rand   *rand.Rand
+	frames uint32
+}
+
+// NewScene returns a Scene with a background at room temperature and a bit of
+// noise.
+func NewScene() *Scene {
+	return &Scene{Background: 8192, Noise: 2, rand: rand.New(rand.NewSource(0))}
+}
+
+// Next renders the scene in a new 80x60 frame.
+//
+// The frame count and the time since startup are incremented at each frame.
+func (s *Scene) Next() *lepton.Frame {
+	if s.rand == nil {
+		s.rand = rand.New(rand.NewSource(0))
+	}
+	rate := s.Rate
+	if rate == 0 {
+		rate = time.Second / 9
+	}
+	f := &lepton.Frame{Gray14: image14bit.NewGray14(image.Rect(0, 0, 80, 60))}
+	s.frames++
+	f.Metadata.FrameCount = s.frames
+	f.Metadata.SinceStartup = time.Duration(s.frames) * rate
+	for y := 0; y < 60; y++ {
+		for x := 0; x < 80; x++ {
+			v := s.Background
+			p := image.Pt(x, y)
+			for _, o := range s.Objects {
+				if p.In(o.Bounds) {
+					v = o.Intensity
+				}
+			}
+			n := float64(v) + s.rand.NormFloat64()*s.Noise
+			if n < 0 {
+				n = 0
+			} else if n > 1<<14-1 {
+				n = 1<<14 - 1
+			}
+			f.SetIntensity14(x, y, image14bit.Intensity14(n))
+		}
+	}
+	return f
+}
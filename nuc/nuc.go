@@ -0,0 +1,182 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package nuc implements a software non-uniformity correction.
+//
+// The camera's own FFC doesn't remove all the fixed pattern noise. Reference
+// frames of a uniform target, like the closed shutter right after a FFC or a
+// lens cap, are used to calculate a per pixel offset. With two references at
+// different temperatures, a per pixel gain is calculated too.
+package nuc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// Reference accumulates frames of a uniform target.
+type Reference struct {
+	bounds image.Rectangle
+	frames int
+	sum    []float64
+}
+
+// Add accumulates a frame. Frames with different bounds than the first one
+// are ignored.
+func (r *Reference) Add(i *image14bit.Gray14) {
+	b := i.Bounds()
+	if r.frames == 0 {
+		r.bounds = b
+		r.sum = make([]float64, b.Dx()*b.Dy())
+	} else if b != r.bounds {
+		return
+	}
+	r.frames++
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		base := i.PixOffset(b.Min.X, y)
+		sum := r.sum[(y-b.Min.Y)*b.Dx():]
+		for x, j := range i.Pix[base : base+b.Dx()] {
+			sum[x] += float64(j)
+		}
+	}
+}
+
+// Frames returns the number of frames accumulated.
+func (r *Reference) Frames() int {
+	return r.frames
+}
+
+// Correction is a per pixel linear correction; each pixel becomes
+// Gains[n]*v + Offsets[n].
+type Correction struct {
+	// Serial is the serial number of the camera, as returned by GetSerial().
+	Serial uint64
+	// Bounds is the bounds of the frames the correction applies to.
+	Bounds  image.Rectangle
+	Offsets []float32
+	// Gains is nil for a one point correction.
+	Gains []float32
+}
+
+// OnePoint calculates offsets so that the reference becomes flat at its mean
+// value.
+func OnePoint(serial uint64, ref *Reference) (*Correction, error) {
+	if ref.frames == 0 {
+		return nil, errors.New("nuc: empty reference")
+	}
+	m, avg := ref.means()
+	c := &Correction{Serial: serial, Bounds: ref.bounds, Offsets: make([]float32, len(m))}
+	for n, v := range m {
+		c.Offsets[n] = float32(avg - v)
+	}
+	return c, nil
+}
+
+// TwoPoint calculates gains and offsets from references of a cold and a hot
+// uniform target.
+func TwoPoint(serial uint64, cold, hot *Reference) (*Correction, error) {
+	if cold.frames == 0 || hot.frames == 0 {
+		return nil, errors.New("nuc: empty reference")
+	}
+	if cold.bounds != hot.bounds {
+		return nil, errors.New("nuc: references have different bounds")
+	}
+	mc, avgc := cold.means()
+	mh, avgh := hot.means()
+	if avgh-avgc < 1 {
+		return nil, errors.New("nuc: hot reference must be hotter than the cold one")
+	}
+	c := &Correction{
+		Serial:  serial,
+		Bounds:  cold.bounds,
+		Offsets: make([]float32, len(mc)),
+		Gains:   make([]float32, len(mc)),
+	}
+	for n := range mc {
+		g := 1.
+		if d := mh[n] - mc[n]; d > 0 {
+			g = (avgh - avgc) / d
+		}
+		c.Gains[n] = float32(g)
+		c.Offsets[n] = float32(avgc - g*mc[n])
+	}
+	return c, nil
+}
+
+// Apply corrects i in place.
+//
+// It does nothing if the bounds of i don't match the correction.
+func (c *Correction) Apply(i *image14bit.Gray14) {
+	b := i.Bounds()
+	if b != c.Bounds {
+		return
+	}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		base := i.PixOffset(b.Min.X, y)
+		n0 := (y - b.Min.Y) * b.Dx()
+		for x, j := range i.Pix[base : base+b.Dx()] {
+			v := float32(j)
+			if c.Gains != nil {
+				v *= c.Gains[n0+x]
+			}
+			v += c.Offsets[n0+x] + 0.5
+			if v < 0 {
+				v = 0
+			} else if v > math.MaxUint16 {
+				v = math.MaxUint16
+			}
+			i.Pix[base+x] = uint16(v)
+		}
+	}
+}
+
+// Path returns the path of the correction for the camera serial in dir.
+func Path(dir string, serial uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("nuc-%x.json", serial))
+}
+
+// Load loads a correction saved with Save.
+func Load(path string) (*Correction, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Correction{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if n := c.Bounds.Dx() * c.Bounds.Dy(); len(c.Offsets) != n || (c.Gains != nil && len(c.Gains) != n) {
+		return nil, fmt.Errorf("%s: invalid correction", path)
+	}
+	return c, nil
+}
+
+// Save saves the correction as a JSON file.
+func (c *Correction) Save(path string) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(b, '\n'), 0600)
+}
+
+// Private details.
+
+// means returns the mean of each pixel and the mean of the whole reference.
+func (r *Reference) means() ([]float64, float64) {
+	out := make([]float64, len(r.sum))
+	avg := 0.
+	for n, s := range r.sum {
+		out[n] = s / float64(r.frames)
+		avg += out[n]
+	}
+	return out, avg / float64(len(out))
+}
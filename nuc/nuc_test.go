@@ -0,0 +1,97 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package nuc
+
+import (
+	"image"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// flat returns a uniform target at v as seen by a sensor with a fixed
+// pattern noise of +/-10 counts and a gain error of +/-10%.
+func flat(v float64) *image14bit.Gray14 {
+	i := image14bit.NewGray14(image.Rect(0, 0, 4, 2))
+	for n := range i.Pix {
+		offset := float64(n%3-1) * 10
+		gain := 1 + float64(n%2)*0.2 - 0.1
+		i.Pix[n] = uint16(8192 + (v-8192)*gain + offset)
+	}
+	return i
+}
+
+func isFlat(t *testing.T, i *image14bit.Gray14, tolerance int) {
+	for _, j := range i.Pix {
+		if d := int(j) - int(i.Pix[0]); d < -tolerance || d > tolerance {
+			t.Fatal(i.Pix)
+		}
+	}
+}
+
+func TestOnePoint(t *testing.T) {
+	r := &Reference{}
+	r.Add(flat(8192))
+	r.Add(flat(8192))
+	c, err := OnePoint(0x1234, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := flat(8192)
+	c.Apply(i)
+	isFlat(t, i, 0)
+	// TLinear counts use the whole 16 bits; 29315 is 20°C.
+	i = flat(8192)
+	i.Pix[0] = 29315
+	i.Pix[1] = 0xFFFF
+	c.Apply(i)
+	if d := int(i.Pix[0]) - 29315; d < -10 || d > 10 || i.Pix[1] < 0xFFFF-10 {
+		t.Fatal(i.Pix)
+	}
+	if _, err := OnePoint(0, &Reference{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestTwoPoint(t *testing.T) {
+	cold, hot := &Reference{}, &Reference{}
+	cold.Add(flat(8000))
+	hot.Add(flat(9000))
+	c, err := TwoPoint(0x1234, cold, hot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := flat(8500)
+	c.Apply(i)
+	isFlat(t, i, 1)
+	if _, err := TwoPoint(0, hot, cold); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	r := &Reference{}
+	r.Add(flat(8192))
+	c, _ := OnePoint(0x1234, r)
+	f, err := ioutil.TempFile("", "nuc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+	if err := c.Save(f.Name()); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c, got) {
+		t.Fatal(got)
+	}
+}
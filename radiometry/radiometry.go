@@ -0,0 +1,214 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package radiometry converts the raw 14 bits counts of a Lepton frame into
+// absolute temperatures.
+//
+// When the camera runs in TLinear mode, the counts are directly proportional
+// to the scene temperature in kelvin. Otherwise, counts are relative to the
+// shutter at the last FFC and are converted using the housing and FPA
+// temperatures found in the frame telemetry: the housing temperature gives the
+// shutter temperature and the FPA temperature compensates for the drift since
+// the FFC.
+package radiometry
+
+import (
+	"errors"
+	"image"
+
+	"periph.io/x/periph/conn/physic"
+	"periph.io/x/periph/devices/lepton"
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// ErrNoTelemetry is returned when a frame doesn't contain the temperatures
+// needed for a non-TLinear conversion. Telemetry must be enabled on the
+// camera.
+var ErrNoTelemetry = errors.New("radiometry: frame has no temperature telemetry")
+
+// Calibration describes how counts relate to temperatures.
+type Calibration struct {
+	// TLinear is true when the camera is configured with TLinear enabled.
+	TLinear bool
+	// Resolution is the temperature of one count in TLinear mode. It is 10mK
+	// in high gain mode and 100mK in low gain mode.
+	Resolution physic.Temperature
+	// Sensitivity is the approximate temperature of one count when TLinear is
+	// not enabled.
+	Sensitivity physic.Temperature
+	// FPADrift is the apparent change of the scene temperature per kelvin of
+	// FPA temperature change since the last FFC when TLinear is not enabled.
+	// It is compensated for.
+	FPADrift float64
+	// Offset is added to every temperature, e.g. to compensate for a known
+	// emissivity error on the target.
+	Offset physic.Temperature
+}
+
+// Default is a calibration for a camera without TLinear.
+//
+// The sensitivity is the commonly used empirical value of 21.7mK per count
+// for the Lepton 2.x.
+var Default = Calibration{
+	Resolution:  10 * physic.MilliKelvin,
+	Sensitivity: 21700 * physic.MicroKelvin,
+	FPADrift:    1,
+}
+
+// Linear is the transfer function from counts to temperatures for a single
+// frame.
+type Linear struct {
+	// Offset is the temperature at count 0.
+	Offset physic.Temperature
+	// Gain is the temperature of one count.
+	Gain physic.Temperature
+	// Max is the highest count. It is 0xFFFF in TLinear mode. When 0, the 14
+	// bits range is assumed.
+	Max image14bit.Intensity14
+}
+
+// Temperature returns the temperature for count v.
+func (l Linear) Temperature(v image14bit.Intensity14) physic.Temperature {
+	return l.Offset + physic.Temperature(v)*l.Gain
+}
+
+// Count returns the nearest count for temperature t, clamped to [0, MaxCount].
+func (l Linear) Count(t physic.Temperature) image14bit.Intensity14 {
+	if l.Gain <= 0 {
+		return 0
+	}
+	c := (t - l.Offset + l.Gain/2) / l.Gain
+	if c < 0 {
+		return 0
+	}
+	if m := l.MaxCount(); c > physic.Temperature(m) {
+		return m
+	}
+	return image14bit.Intensity14(c)
+}
+
+// MaxCount returns the highest count, which is Max or the highest 14 bits
+// value if Max is not set.
+func (l Linear) MaxCount() image14bit.Intensity14 {
+	if l.Max == 0 {
+		return maxCount
+	}
+	return l.Max
+}
+
+// ForFrame returns the transfer function to use for a frame with metadata m.
+//
+// In TLinear mode the metadata is not used and counts use the whole 16 bits.
+// Otherwise the count 8192 corresponds to the shutter, which is assumed to be
+// at the housing temperature of the last FFC. The FPA temperature is used for
+// the shutter when the housing temperature is not available. When both the
+// current and the last FFC FPA temperatures are known, the FPA drift since
+// the FFC is compensated for with FPADrift.
+func (c *Calibration) ForFrame(m *lepton.Metadata) (Linear, error) {
+	if c.TLinear {
+		return Linear{Offset: c.Offset, Gain: c.Resolution, Max: 0xFFFF}, nil
+	}
+	ref := m.FFCTempHousing
+	if ref == 0 {
+		ref = m.TempHousing
+	}
+	if ref == 0 {
+		ref = m.Temp
+	}
+	if ref == 0 {
+		return Linear{}, ErrNoTelemetry
+	}
+	if m.Temp != 0 && m.FFCTemp != 0 {
+		ref -= physic.Temperature(c.FPADrift * float64(m.Temp-m.FFCTemp))
+	}
+	return Linear{Offset: ref + c.Offset - shutterCount*c.Sensitivity, Gain: c.Sensitivity, Max: maxCount}, nil
+}
+
+// Convert returns the temperature image for frame f.
+func Convert(f *lepton.Frame, c *Calibration) (*Image, error) {
+	l, err := c.ForFrame(&f.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	b := f.Bounds()
+	dst := NewImage(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		base := f.PixOffset(b.Min.X, y)
+		dBase := dst.PixOffset(b.Min.X, y)
+		for x, v := range f.Pix[base : base+b.Dx()] {
+			dst.Pix[dBase+x] = l.Temperature(image14bit.Intensity14(v))
+		}
+	}
+	return dst, nil
+}
+
+// Image is a per pixel temperature image.
+type Image struct {
+	// Pix holds the temperatures. The pixel at (x, y) is at
+	// Pix[(y-Rect.Min.Y)*Stride + (x-Rect.Min.X)].
+	Pix    []physic.Temperature
+	Stride int
+	Rect   image.Rectangle
+}
+
+// NewImage returns a new Image with the given bounds.
+func NewImage(r image.Rectangle) *Image {
+	return &Image{Pix: make([]physic.Temperature, r.Dx()*r.Dy()), Stride: r.Dx(), Rect: r}
+}
+
+// Bounds returns the domain for which At can return non-zero values.
+func (i *Image) Bounds() image.Rectangle {
+	return i.Rect
+}
+
+// PixOffset returns the index of the element of Pix that corresponds to the
+// pixel at (x, y).
+func (i *Image) PixOffset(x, y int) int {
+	return (y-i.Rect.Min.Y)*i.Stride + (x - i.Rect.Min.X)
+}
+
+// TemperatureAt returns the temperature at (x, y).
+func (i *Image) TemperatureAt(x, y int) physic.Temperature {
+	if !(image.Point{x, y}.In(i.Rect)) {
+		return 0
+	}
+	return i.Pix[i.PixOffset(x, y)]
+}
+
+// SetTemperature sets the temperature at (x, y).
+func (i *Image) SetTemperature(x, y int, t physic.Temperature) {
+	if !(image.Point{x, y}.In(i.Rect)) {
+		return
+	}
+	i.Pix[i.PixOffset(x, y)] = t
+}
+
+// KelvinAt returns the temperature at (x, y) in kelvin.
+func (i *Image) KelvinAt(x, y int) float64 {
+	return Kelvin(i.TemperatureAt(x, y))
+}
+
+// CelsiusAt returns the temperature at (x, y) in degree Celsius.
+func (i *Image) CelsiusAt(x, y int) float64 {
+	return Celsius(i.TemperatureAt(x, y))
+}
+
+// Kelvin returns t in kelvin.
+func Kelvin(t physic.Temperature) float64 {
+	return float64(t) / float64(physic.Kelvin)
+}
+
+// Celsius returns t in degree Celsius.
+func Celsius(t physic.Temperature) float64 {
+	return float64(t-physic.ZeroCelsius) / float64(physic.Kelvin)
+}
+
+// Private details.
+
+const (
+	// shutterCount is the count of a pixel seeing the shutter right after a
+	// FFC.
+	shutterCount = 8192
+	maxCount     = 1<<14 - 1
+)
@@ -0,0 +1,97 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package radiometry
+
+import (
+	"image"
+	"testing"
+
+	"periph.io/x/periph/conn/physic"
+	"periph.io/x/periph/devices/lepton"
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+func TestTLinear(t *testing.T) {
+	c := Default
+	c.TLinear = true
+	f := &lepton.Frame{Gray14: image14bit.NewGray14(image.Rect(0, 0, 2, 1))}
+	// 29315 * 10mK = 293.15K = 20°C.
+	f.Pix[0] = 29315
+	f.Pix[1] = 0xFFFF
+	img, err := Convert(f, &c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := img.CelsiusAt(0, 0); v != 20 {
+		t.Fatal(v)
+	}
+	if v := img.KelvinAt(1, 0); v != 655.35 {
+		t.Fatal(v)
+	}
+	l, _ := c.ForFrame(&f.Metadata)
+	if v := l.Count(img.TemperatureAt(0, 0)); v != 29315 {
+		t.Fatal(v)
+	}
+	if v := l.Count(100 * physic.Kelvin); v != 10000 {
+		t.Fatal(v)
+	}
+	if v := l.Count(1000 * physic.Kelvin); v != 0xFFFF {
+		t.Fatal(v)
+	}
+}
+
+func TestFallback(t *testing.T) {
+	c := Default
+	f := &lepton.Frame{Gray14: image14bit.NewGray14(image.Rect(0, 0, 2, 1))}
+	f.Pix[0] = 8192
+	f.Pix[1] = 8192 + 1000
+	if _, err := Convert(f, &c); err != ErrNoTelemetry {
+		t.Fatal(err)
+	}
+	f.Metadata.Temp = physic.ZeroCelsius + 30*physic.Celsius
+	f.Metadata.TempHousing = physic.ZeroCelsius + 25*physic.Celsius
+	img, err := Convert(f, &c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := img.CelsiusAt(0, 0); v != 25 {
+		t.Fatal(v)
+	}
+	if v := img.CelsiusAt(1, 0); v < 46.6 || v > 46.8 {
+		t.Fatal(v)
+	}
+	if v := img.TemperatureAt(5, 5); v != 0 {
+		t.Fatal(v)
+	}
+	l, _ := c.ForFrame(&f.Metadata)
+	if v := l.Count(1000 * physic.Kelvin); v != 16383 {
+		t.Fatal(v)
+	}
+}
+
+func TestFPADrift(t *testing.T) {
+	c := Default
+	m := lepton.Metadata{
+		Temp:           physic.ZeroCelsius + 32*physic.Celsius,
+		TempHousing:    physic.ZeroCelsius + 26*physic.Celsius,
+		FFCTemp:        physic.ZeroCelsius + 30*physic.Celsius,
+		FFCTempHousing: physic.ZeroCelsius + 25*physic.Celsius,
+	}
+	l, err := c.ForFrame(&m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The shutter was at 25°C at the FFC and the FPA warmed up by 2K since.
+	if v := Celsius(l.Temperature(8192)); v != 23 {
+		t.Fatal(v)
+	}
+	c.FPADrift = 0
+	if l, err = c.ForFrame(&m); err != nil {
+		t.Fatal(err)
+	}
+	if v := Celsius(l.Temperature(8192)); v != 25 {
+		t.Fatal(v)
+	}
+}
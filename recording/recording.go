@@ -0,0 +1,638 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package recording saves the raw frame stream of a camera to disk and reads
+// it back.
+//
+// A recording is made of little endian values. It starts with a 28 bytes
+// header:
+//
+//	[8]byte magic "LEPTREC\x00"
+//	uint16  version, currently 1
+//	uint16  width
+//	uint16  height
+//	uint16  reserved, 0
+//	uint64  serial of the camera, as returned by GetSerial()
+//	uint32  CRC-32C of the previous 24 bytes
+//
+// It is followed by records:
+//
+//	uint32  length n of the body
+//	[n]byte body; the first byte is the record type
+//	uint32  CRC-32C of the body
+//
+// A frame record (type 1) is:
+//
+//	uint8   1
+//	int64   wall clock time, in nanoseconds since the Unix epoch
+//	int64   SinceStartup, in nanoseconds
+//	uint32  FrameCount
+//	uint16  AvgValue
+//	int64   Temp, in nanokelvin
+//	int64   TempHousing, in nanokelvin
+//	uint16  RawTemp
+//	uint16  RawTempHousing
+//	int64   FFCSince, in nanoseconds
+//	int64   FFCTemp, in nanokelvin
+//	int64   FFCTempHousing, in nanokelvin
+//	uint8   FFCState
+//	uint8   flags; bit 0 is FFCDesired, bit 1 is Overtemp
+//	[]uint16 width*height pixels, row by row
+//
+// An index record (type 2) lists the frames written since the previous
+// index record:
+//
+//	uint8   2
+//	int64   offset of the previous index record, -1 if none
+//	uint32  number of entries
+//	[]struct{ int64 time; int64 offset of the frame record }
+//
+// When the recording is closed cleanly, a 16 bytes trailer is appended:
+//
+//	[8]byte magic "LEPTEND\x00"
+//	int64   offset of the last index record, -1 if none
+//
+// The trailer lets a reader load the index by following the chain of index
+// records backward. When it is missing, for example after a power loss, the
+// records are scanned from the start instead.
+package recording
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"periph.io/x/periph/conn/physic"
+	"periph.io/x/periph/devices/lepton"
+	"periph.io/x/periph/devices/lepton/cci"
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+// Opts is the options to write a recording.
+type Opts struct {
+	// IndexInterval is the number of frames between index records. The file
+	// is synced to disk after each index record, so at most this number of
+	// frames is lost on power loss.
+	IndexInterval int
+}
+
+// DefaultOpts writes an index record every 10 seconds at 9 frames per
+// second.
+var DefaultOpts = Opts{IndexInterval: 90}
+
+// Writer appends frames to a recording.
+//
+// It is safe for concurrent use.
+type Writer struct {
+	mu        sync.Mutex
+	f         *os.File
+	opts      Opts
+	size      image.Point
+	serial    uint64
+	offset    int64   // End of the last record.
+	prevIndex int64   // Offset of the last index record, -1 if none.
+	pending   []entry // Frames written since the last index record.
+}
+
+// Create creates a recording for frames of size bounds from the camera
+// serial. An existing file is overwritten.
+func Create(path string, bounds image.Rectangle, serial uint64, opts *Opts) (*Writer, error) {
+	if opts == nil {
+		opts = &DefaultOpts
+	}
+	if err := checkOpts(opts); err != nil {
+		return nil, err
+	}
+	if bounds.Empty() || frameHeaderSize+2*bounds.Dx()*bounds.Dy() > maxRecord {
+		return nil, fmt.Errorf("recording: unsupported frame size %s", bounds.Size())
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	w := &Writer{f: f, opts: *opts, size: bounds.Size(), serial: serial, offset: headerSize, prevIndex: -1}
+	if _, err := f.Write(encodeHeader(w.size, serial)); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// Append opens an existing recording to add frames to it.
+//
+// The recording must be for frames of size bounds from the camera serial,
+// otherwise it is left untouched and an error is returned. If the recording
+// wasn't closed cleanly, the incomplete or corrupted records at the end are
+// truncated first.
+func Append(path string, bounds image.Rectangle, serial uint64, opts *Opts) (*Writer, error) {
+	if opts == nil {
+		opts = &DefaultOpts
+	}
+	if err := checkOpts(opts); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	w, err := reopen(f, bounds.Size(), serial, opts)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("recording: %s: %v", path, err)
+	}
+	return w, nil
+}
+
+// Write appends a frame captured at time t.
+func (w *Writer) Write(f *lepton.Frame, t time.Time) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.f == nil {
+		return errors.New("recording: writer is closed")
+	}
+	if f.Bounds().Size() != w.size {
+		return fmt.Errorf("recording: frame size %s doesn't match %s", f.Bounds().Size(), w.size)
+	}
+	if err := w.writeRecord(encodeFrame(f, t)); err != nil {
+		return err
+	}
+	w.pending = append(w.pending, entry{t.UnixNano(), w.offset})
+	w.offset += recordSize(frameHeaderSize + 2*w.size.X*w.size.Y)
+	if len(w.pending) >= w.opts.IndexInterval {
+		return w.writeIndex()
+	}
+	return nil
+}
+
+// Close writes the index for the last frames and the trailer.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.f == nil {
+		return errors.New("recording: writer is closed")
+	}
+	var err error
+	if len(w.pending) != 0 {
+		err = w.writeIndex()
+	}
+	if err == nil {
+		var t [trailerSize]byte
+		copy(t[:], trailerMagic)
+		binary.LittleEndian.PutUint64(t[8:], uint64(w.prevIndex))
+		if _, err = w.f.WriteAt(t[:], w.offset); err == nil {
+			err = w.f.Sync()
+		}
+	}
+	if err2 := w.f.Close(); err == nil {
+		err = err2
+	}
+	w.f = nil
+	return err
+}
+
+// Reader reads the frames of a recording.
+//
+// It is not safe for concurrent use.
+type Reader struct {
+	f       *os.File
+	size    image.Point
+	serial  uint64
+	entries []entry
+	next    int
+}
+
+// Open opens a recording for reading.
+//
+// The recording may still be written to; only the frames written up to
+// this point are visible.
+func Open(path string) (*Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := newReader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("recording: %s: %v", path, err)
+	}
+	return r, nil
+}
+
+// Serial returns the serial number of the camera that made the recording.
+func (r *Reader) Serial() uint64 {
+	return r.serial
+}
+
+// Bounds returns the bounds of the frames.
+func (r *Reader) Bounds() image.Rectangle {
+	return image.Rectangle{Max: r.size}
+}
+
+// Len returns the number of frames.
+func (r *Reader) Len() int {
+	return len(r.entries)
+}
+
+// Seek positions the reader at the first frame captured at or after t.
+//
+// The frames are expected to be recorded in chronological order.
+func (r *Reader) Seek(t time.Time) {
+	ns := t.UnixNano()
+	r.next = sort.Search(len(r.entries), func(i int) bool { return r.entries[i].time >= ns })
+}
+
+// Next returns the next frame and the time it was captured. It returns
+// io.EOF after the last frame.
+func (r *Reader) Next() (*lepton.Frame, time.Time, error) {
+	if r.next >= len(r.entries) {
+		return nil, time.Time{}, io.EOF
+	}
+	e := r.entries[r.next]
+	body, err := readRecord(r.f, e.offset)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	f, t, err := decodeFrame(body, r.size)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	r.next++
+	return f, t, nil
+}
+
+// Close closes the file.
+func (r *Reader) Close() error {
+	return r.f.Close()
+}
+
+// Private details.
+
+const (
+	headerMagic     = "LEPTREC\x00"
+	headerSize      = 28
+	version         = 1
+	trailerMagic    = "LEPTEND\x00"
+	trailerSize     = 16
+	recFrame        = 1
+	recIndex        = 2
+	frameHeaderSize = 69
+	indexHeaderSize = 13
+	entrySize       = 16
+	// maxRecord protects against corrupted lengths. An index record can hold
+	// 65536 entries.
+	maxRecord = 1 << 20
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// entry is the location of a frame.
+type entry struct {
+	time   int64 // Nanoseconds since the Unix epoch.
+	offset int64
+}
+
+func checkOpts(opts *Opts) error {
+	if opts.IndexInterval <= 0 || indexHeaderSize+entrySize*opts.IndexInterval > maxRecord {
+		return errors.New("recording: invalid IndexInterval")
+	}
+	return nil
+}
+
+// recordSize returns the size of a record with a body of n bytes.
+func recordSize(n int) int64 {
+	return int64(4 + n + 4)
+}
+
+// writeRecord writes a record at w.offset. It doesn't update w.offset.
+func (w *Writer) writeRecord(body []byte) error {
+	b := make([]byte, 4, recordSize(len(body)))
+	binary.LittleEndian.PutUint32(b, uint32(len(body)))
+	b = append(b, body...)
+	b = append(b, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(b[len(b)-4:], crc32.Checksum(body, castagnoli))
+	_, err := w.f.WriteAt(b, w.offset)
+	return err
+}
+
+// writeIndex writes the index record for the pending frames and syncs the
+// file.
+func (w *Writer) writeIndex() error {
+	b := make([]byte, indexHeaderSize, indexHeaderSize+entrySize*len(w.pending))
+	b[0] = recIndex
+	binary.LittleEndian.PutUint64(b[1:], uint64(w.prevIndex))
+	binary.LittleEndian.PutUint32(b[9:], uint32(len(w.pending)))
+	for _, e := range w.pending {
+		b = appendUint64(b, uint64(e.time))
+		b = appendUint64(b, uint64(e.offset))
+	}
+	if err := w.writeRecord(b); err != nil {
+		return err
+	}
+	w.prevIndex = w.offset
+	w.offset += recordSize(len(b))
+	w.pending = w.pending[:0]
+	return w.f.Sync()
+}
+
+// reopen prepares a writer to append to f.
+func reopen(f *os.File, size image.Point, serial uint64, opts *Opts) (*Writer, error) {
+	h, err := readHeader(f)
+	if err != nil {
+		return nil, err
+	}
+	// Nothing is modified until the file is known to be the right one.
+	if h.size != size || h.serial != serial {
+		return nil, fmt.Errorf("recorded with camera %x at %s, not camera %x at %s", h.serial, h.size, serial, size)
+	}
+	st, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	w := &Writer{f: f, opts: *opts, size: h.size, serial: h.serial, prevIndex: -1}
+	if last, ok := readTrailer(f, st.Size()); ok {
+		// Closed cleanly, only the trailer needs to go.
+		w.offset = st.Size() - trailerSize
+		w.prevIndex = last
+	} else {
+		s, err := scan(f, st.Size())
+		if err != nil {
+			return nil, err
+		}
+		// Everything up to the last index record was synced. Verify the frames
+		// after it.
+		w.offset = s.end
+		w.prevIndex = s.lastIndex
+		for i, e := range s.entries[s.indexed:] {
+			if _, err := readRecord(f, e.offset); err != nil {
+				w.offset = e.offset
+				s.entries = s.entries[:s.indexed+i]
+				break
+			}
+		}
+		w.pending = s.entries[s.indexed:]
+	}
+	if err := f.Truncate(w.offset); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// header is the decoded file header.
+type header struct {
+	size   image.Point
+	serial uint64
+}
+
+func encodeHeader(size image.Point, serial uint64) []byte {
+	b := make([]byte, headerSize)
+	copy(b, headerMagic)
+	binary.LittleEndian.PutUint16(b[8:], version)
+	binary.LittleEndian.PutUint16(b[10:], uint16(size.X))
+	binary.LittleEndian.PutUint16(b[12:], uint16(size.Y))
+	binary.LittleEndian.PutUint64(b[16:], serial)
+	binary.LittleEndian.PutUint32(b[24:], crc32.Checksum(b[:24], castagnoli))
+	return b
+}
+
+func readHeader(f io.ReaderAt) (header, error) {
+	var b [headerSize]byte
+	if _, err := f.ReadAt(b[:], 0); err != nil {
+		if err == io.EOF {
+			err = errors.New("not a recording")
+		}
+		return header{}, err
+	}
+	if string(b[:8]) != headerMagic {
+		return header{}, errors.New("not a recording")
+	}
+	if binary.LittleEndian.Uint32(b[24:]) != crc32.Checksum(b[:24], castagnoli) {
+		return header{}, errors.New("corrupted header")
+	}
+	if v := binary.LittleEndian.Uint16(b[8:]); v != version {
+		return header{}, fmt.Errorf("unsupported version %d", v)
+	}
+	return header{
+		size:   image.Pt(int(binary.LittleEndian.Uint16(b[10:])), int(binary.LittleEndian.Uint16(b[12:]))),
+		serial: binary.LittleEndian.Uint64(b[16:]),
+	}, nil
+}
+
+// readTrailer returns the offset of the last index record, if the file has
+// a trailer.
+func readTrailer(f io.ReaderAt, size int64) (int64, bool) {
+	if size < headerSize+trailerSize {
+		return 0, false
+	}
+	var b [trailerSize]byte
+	if _, err := f.ReadAt(b[:], size-trailerSize); err != nil || string(b[:8]) != trailerMagic {
+		return 0, false
+	}
+	last := int64(binary.LittleEndian.Uint64(b[8:]))
+	if last != -1 {
+		// Confirm it points to an index record, the trailer magic could be the
+		// end of a truncated frame.
+		body, err := readRecord(f, last)
+		if err != nil || body[0] != recIndex || last+recordSize(len(body)) != size-trailerSize {
+			return 0, false
+		}
+	} else if size != headerSize+trailerSize {
+		return 0, false
+	}
+	return last, true
+}
+
+// readRecord reads and verifies the record at offset, returning its body.
+func readRecord(f io.ReaderAt, offset int64) ([]byte, error) {
+	var hdr [4]byte
+	if _, err := f.ReadAt(hdr[:], offset); err != nil {
+		return nil, err
+	}
+	n := binary.LittleEndian.Uint32(hdr[:])
+	if n == 0 || n > maxRecord {
+		return nil, errors.New("recording: corrupted record")
+	}
+	b := make([]byte, n+4)
+	if _, err := f.ReadAt(b, offset+4); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	if binary.LittleEndian.Uint32(b[n:]) != crc32.Checksum(b[:n], castagnoli) {
+		return nil, errors.New("recording: corrupted record")
+	}
+	return b[:n], nil
+}
+
+// scanResult is the result of scan.
+type scanResult struct {
+	entries   []entry
+	indexed   int   // Number of entries covered by an index record.
+	lastIndex int64 // Offset of the last index record, -1 if none.
+	end       int64 // End of the last complete record.
+}
+
+// scan walks the records from the start of the file without reading the
+// pixels, until the end of the file or the first incomplete record.
+//
+// Only the index records are verified.
+func scan(f io.ReaderAt, size int64) (scanResult, error) {
+	s := scanResult{lastIndex: -1, end: headerSize}
+	var hdr [4 + 1 + 8]byte
+	for s.end+int64(len(hdr)) <= size {
+		if _, err := f.ReadAt(hdr[:], s.end); err != nil {
+			return s, err
+		}
+		n := binary.LittleEndian.Uint32(hdr[:])
+		if n == 0 || n > maxRecord || s.end+recordSize(int(n)) > size {
+			break
+		}
+		switch hdr[4] {
+		case recFrame:
+			s.entries = append(s.entries, entry{int64(binary.LittleEndian.Uint64(hdr[5:])), s.end})
+		case recIndex:
+			if _, err := readRecord(f, s.end); err != nil {
+				return s, nil
+			}
+			s.indexed = len(s.entries)
+			s.lastIndex = s.end
+		default:
+			return s, nil
+		}
+		s.end += recordSize(int(n))
+	}
+	return s, nil
+}
+
+func newReader(f *os.File) (*Reader, error) {
+	h, err := readHeader(f)
+	if err != nil {
+		return nil, err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	r := &Reader{f: f, size: h.size, serial: h.serial}
+	if last, ok := readTrailer(f, st.Size()); ok {
+		// Follow the index chain backward.
+		var chunks [][]entry
+		for last != -1 {
+			body, err := readRecord(f, last)
+			if err != nil {
+				return nil, err
+			}
+			if body[0] != recIndex || len(body) < indexHeaderSize {
+				return nil, errors.New("corrupted index")
+			}
+			n := int(binary.LittleEndian.Uint32(body[9:]))
+			if len(body) != indexHeaderSize+entrySize*n {
+				return nil, errors.New("corrupted index")
+			}
+			c := make([]entry, n)
+			for i := range c {
+				e := body[indexHeaderSize+entrySize*i:]
+				c[i] = entry{int64(binary.LittleEndian.Uint64(e)), int64(binary.LittleEndian.Uint64(e[8:]))}
+			}
+			chunks = append(chunks, c)
+			prev := int64(binary.LittleEndian.Uint64(body[1:]))
+			if prev >= last {
+				return nil, errors.New("corrupted index")
+			}
+			last = prev
+		}
+		for i := len(chunks) - 1; i >= 0; i-- {
+			r.entries = append(r.entries, chunks[i]...)
+		}
+		return r, nil
+	}
+	s, err := scan(f, st.Size())
+	if err != nil {
+		return nil, err
+	}
+	r.entries = s.entries
+	return r, nil
+}
+
+func encodeFrame(f *lepton.Frame, t time.Time) []byte {
+	m := &f.Metadata
+	b := make([]byte, 0, frameHeaderSize+2*len(f.Pix))
+	b = append(b, recFrame)
+	b = appendUint64(b, uint64(t.UnixNano()))
+	b = appendUint64(b, uint64(m.SinceStartup))
+	b = appendUint32(b, m.FrameCount)
+	b = appendUint16(b, m.AvgValue)
+	b = appendUint64(b, uint64(m.Temp))
+	b = appendUint64(b, uint64(m.TempHousing))
+	b = appendUint16(b, m.RawTemp)
+	b = appendUint16(b, m.RawTempHousing)
+	b = appendUint64(b, uint64(m.FFCSince))
+	b = appendUint64(b, uint64(m.FFCTemp))
+	b = appendUint64(b, uint64(m.FFCTempHousing))
+	var flags byte
+	if m.FFCDesired {
+		flags |= 1
+	}
+	if m.Overtemp {
+		flags |= 2
+	}
+	b = append(b, byte(m.FFCState), flags)
+	r := f.Bounds()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		base := f.PixOffset(r.Min.X, y)
+		for _, v := range f.Pix[base : base+r.Dx()] {
+			b = appendUint16(b, v)
+		}
+	}
+	return b
+}
+
+func decodeFrame(b []byte, size image.Point) (*lepton.Frame, time.Time, error) {
+	if len(b) != frameHeaderSize+2*size.X*size.Y || b[0] != recFrame {
+		return nil, time.Time{}, errors.New("recording: corrupted frame")
+	}
+	le := binary.LittleEndian
+	t := time.Unix(0, int64(le.Uint64(b[1:])))
+	f := &lepton.Frame{
+		Gray14: image14bit.NewGray14(image.Rectangle{Max: size}),
+		Metadata: lepton.Metadata{
+			SinceStartup:   time.Duration(le.Uint64(b[9:])),
+			FrameCount:     le.Uint32(b[17:]),
+			AvgValue:       le.Uint16(b[21:]),
+			Temp:           physic.Temperature(le.Uint64(b[23:])),
+			TempHousing:    physic.Temperature(le.Uint64(b[31:])),
+			RawTemp:        le.Uint16(b[39:]),
+			RawTempHousing: le.Uint16(b[41:]),
+			FFCSince:       time.Duration(le.Uint64(b[43:])),
+			FFCTemp:        physic.Temperature(le.Uint64(b[51:])),
+			FFCTempHousing: physic.Temperature(le.Uint64(b[59:])),
+			FFCState:       cci.FFCState(b[67]),
+			FFCDesired:     b[68]&1 != 0,
+			Overtemp:       b[68]&2 != 0,
+		},
+	}
+	for i := range f.Pix {
+		f.Pix[i] = le.Uint16(b[frameHeaderSize+2*i:])
+	}
+	return f, t, nil
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v), byte(v>>8))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	return appendUint32(appendUint32(b, uint32(v)), uint32(v>>32))
+}
@@ -0,0 +1,252 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package recording
+
+import (
+	"image"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"periph.io/x/periph/conn/physic"
+	"periph.io/x/periph/devices/lepton"
+	"periph.io/x/periph/devices/lepton/cci"
+	"periph.io/x/periph/devices/lepton/image14bit"
+)
+
+func TestRecording(t *testing.T) {
+	p, cleanup := tempPath(t)
+	defer cleanup()
+	w, err := Create(p, image.Rect(0, 0, 80, 60), 0x1234, &Opts{IndexInterval: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 25; i++ {
+		if err := w.Write(frame(i), start.Add(time.Duration(i)*time.Second)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Write(&lepton.Frame{Gray14: image14bit.NewGray14(image.Rect(0, 0, 2, 2))}, start); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(frame(0), start); err == nil {
+		t.Fatal("expected error")
+	}
+
+	r, err := Open(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if r.Len() != 25 || r.Serial() != 0x1234 || r.Bounds() != image.Rect(0, 0, 80, 60) {
+		t.Fatal(r.Len(), r.Serial(), r.Bounds())
+	}
+	readAll(t, r, 0, 25)
+	r.Seek(start.Add(11500 * time.Millisecond))
+	readAll(t, r, 12, 25)
+	r.Seek(start.Add(time.Hour))
+	if _, _, err := r.Next(); err != io.EOF {
+		t.Fatal(err)
+	}
+
+	// Appending after a clean close.
+	// Another camera's recording is left untouched.
+	st, err := os.Stat(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Append(p, image.Rect(0, 0, 80, 60), 0x4321, nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err = Append(p, image.Rect(0, 0, 160, 120), 0x1234, nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if st2, err := os.Stat(p); err != nil || st2.Size() != st.Size() {
+		t.Fatal(st2.Size(), st.Size(), err)
+	}
+	if w, err = Append(p, image.Rect(0, 0, 80, 60), 0x1234, &Opts{IndexInterval: 10}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 25; i < 30; i++ {
+		if err := w.Write(frame(i), start.Add(time.Duration(i)*time.Second)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r2, err := Open(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r2.Close()
+	readAll(t, r2, 0, 30)
+}
+
+func TestRecording_powerLoss(t *testing.T) {
+	p, cleanup := tempPath(t)
+	defer cleanup()
+	w, err := Create(p, image.Rect(0, 0, 80, 60), 1, &Opts{IndexInterval: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 25; i++ {
+		if err := w.Write(frame(i), start.Add(time.Duration(i)*time.Second)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Simulate a power loss: the writer is never closed and the last frame is
+	// only partially written.
+	w.f.Close()
+	st, err := os.Stat(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(p, st.Size()-100); err != nil {
+		t.Fatal(err)
+	}
+
+	// The complete frames are readable without the trailer.
+	r, err := Open(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readAll(t, r, 0, 24)
+	r.Close()
+
+	// Corrupt the pixels of the last complete frame. Its record is complete
+	// but the checksum doesn't match.
+	f, err := os.OpenFile(p, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteAt([]byte{0xFF, 0xFF}, st.Size()-recordSize(frameHeaderSize+2*80*60)-200); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	if w, err = Append(p, image.Rect(0, 0, 80, 60), 1, &Opts{IndexInterval: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(frame(23), start.Add(23*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if r, err = Open(p); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if r.Len() != 24 {
+		t.Fatal(r.Len())
+	}
+	readAll(t, r, 0, 24)
+}
+
+func TestRecording_empty(t *testing.T) {
+	p, cleanup := tempPath(t)
+	defer cleanup()
+	w, err := Create(p, image.Rect(0, 0, 80, 60), 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := Open(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if _, _, err := r.Next(); err != io.EOF {
+		t.Fatal(err)
+	}
+}
+
+func TestOpen_err(t *testing.T) {
+	p, cleanup := tempPath(t)
+	defer cleanup()
+	if err := ioutil.WriteFile(p, []byte("not a recording, really not a recording"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(p); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := Append(p, image.Rect(0, 0, 80, 60), 1, nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := Create(p, image.Rect(0, 0, 80, 60), 1, &Opts{}); err == nil {
+		t.Fatal("expected error")
+	}
+	// A frame must fit in a record.
+	if _, err := Create(p, image.Rect(0, 0, 1024, 1024), 1, nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := Create(p, image.Rectangle{}, 1, nil); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+// Private details.
+
+var start = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+func tempPath(t *testing.T) (string, func()) {
+	d, err := ioutil.TempDir("", "recording")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(d, "rec.lrec"), func() { os.RemoveAll(d) }
+}
+
+// frame returns a frame with distinctive content for index i.
+func frame(i int) *lepton.Frame {
+	f := &lepton.Frame{
+		Gray14: image14bit.NewGray14(image.Rect(0, 0, 80, 60)),
+		Metadata: lepton.Metadata{
+			SinceStartup: time.Duration(i) * time.Second,
+			FrameCount:   uint32(i),
+			Temp:         physic.ZeroCelsius + physic.Temperature(30000+i)*physic.MilliKelvin,
+			FFCState:     cci.FFCComplete,
+			Overtemp:     i%2 == 0,
+		},
+	}
+	for j := range f.Pix {
+		f.Pix[j] = uint16((i*100 + j) & (1<<14 - 1))
+	}
+	return f
+}
+
+// readAll reads the frames from index from to to and verifies them.
+func readAll(t *testing.T, r *Reader, from, to int) {
+	for i := from; i < to; i++ {
+		f, ts, err := r.Next()
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		want := frame(i)
+		if f.Metadata != want.Metadata {
+			t.Fatalf("%d: %+v != %+v", i, f.Metadata, want.Metadata)
+		}
+		for j := range f.Pix {
+			if f.Pix[j] != want.Pix[j] {
+				t.Fatalf("%d: pixel %d", i, j)
+			}
+		}
+		if !ts.Equal(start.Add(time.Duration(i) * time.Second)) {
+			t.Fatalf("%d: %s", i, ts)
+		}
+	}
+	if _, _, err := r.Next(); err != io.EOF {
+		t.Fatal(err)
+	}
+}
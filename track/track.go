@@ -0,0 +1,212 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package track follows detected objects across frames and assigns them
+// stable IDs.
+//
+// Detections are matched to the existing tracks by distance to the position
+// predicted from each track's velocity. A track that is not detected is kept
+// for a few frames, so an object briefly occluded keeps its ID.
+package track
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/maruel/go-lepton/blob"
+	"periph.io/x/periph/devices/lepton"
+)
+
+// Opts controls the tracker.
+type Opts struct {
+	// MaxDistance is the maximum distance in pixels between the predicted
+	// position of a track and a detection for them to be matched.
+	MaxDistance float64
+	// MaxMissed is the number of consecutive frames a track can go undetected
+	// before it is dropped.
+	MaxMissed int
+	// MinHits is the number of detections needed before a track is reported.
+	// This filters out spurious detections.
+	MinHits int
+	// History is the maximum number of positions kept in a trajectory.
+	History int
+}
+
+// DefaultOpts is the default options, suitable for people walking at about
+// 3 meters from the camera at 9Hz.
+var DefaultOpts = Opts{MaxDistance: 10, MaxMissed: 9, MinHits: 3, History: 90}
+
+// Sample is a position of a track at a point in time.
+type Sample struct {
+	// Time is the time since the camera started, from
+	// lepton.Metadata.SinceStartup.
+	Time time.Duration
+	Pos  blob.Point
+}
+
+// Track is an object followed across frames.
+type Track struct {
+	// ID is unique for the lifetime of the Tracker, starting at 1.
+	ID int
+	// Blob is the last detection matched to this track.
+	Blob blob.Blob
+	// Trajectory is the positions of the track, oldest first. It is capped at
+	// Opts.History.
+	Trajectory []Sample
+	// Velocity is the smoothed velocity in pixels per second.
+	Velocity blob.Point
+	// FirstSeen and LastSeen are the times of the first and last detections.
+	FirstSeen time.Duration
+	LastSeen  time.Duration
+	// Hits is the number of frames the track was detected in.
+	Hits int
+	// Missed is the number of consecutive frames the track was not detected
+	// in. It is non zero while the object is occluded.
+	Missed int
+}
+
+// Pos returns the last known position.
+func (t *Track) Pos() blob.Point {
+	return t.Trajectory[len(t.Trajectory)-1].Pos
+}
+
+// Speed returns the smoothed speed in pixels per second.
+func (t *Track) Speed() float64 {
+	return math.Hypot(t.Velocity.X, t.Velocity.Y)
+}
+
+// Lifetime returns how long the track has been followed.
+func (t *Track) Lifetime() time.Duration {
+	return t.LastSeen - t.FirstSeen
+}
+
+// Tracker assigns persistent IDs to detections.
+//
+// It is not safe for concurrent use.
+type Tracker struct {
+	opts   Opts
+	tracks []*Track
+	nextID int
+}
+
+// New returns a Tracker.
+//
+// If opts is nil, DefaultOpts is used.
+func New(opts *Opts) *Tracker {
+	t := &Tracker{opts: DefaultOpts, nextID: 1}
+	if opts != nil {
+		t.opts = *opts
+	}
+	return t
+}
+
+// Update matches the detections of frame f to the tracks.
+//
+// It returns the tracks confirmed with at least MinHits detections, including
+// the ones currently occluded, and the confirmed tracks that were dropped
+// during this update. The returned values are copies owned by the caller.
+func (t *Tracker) Update(m *lepton.Metadata, detections []blob.Blob) (active, lost []Track) {
+	now := m.SinceStartup
+
+	// Greedily match the closest pairs first.
+	type pair struct {
+		track, det int
+		dist       float64
+	}
+	var pairs []pair
+	for i, tr := range t.tracks {
+		p := tr.Pos()
+		e := (now - tr.LastSeen).Seconds()
+		p.X += tr.Velocity.X * e
+		p.Y += tr.Velocity.Y * e
+		for j := range detections {
+			c := detections[j].Centroid
+			if d := math.Hypot(c.X-p.X, c.Y-p.Y); d <= t.opts.MaxDistance {
+				pairs = append(pairs, pair{i, j, d})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].dist < pairs[j].dist })
+	matchedTrack := make([]bool, len(t.tracks))
+	matchedDet := make([]bool, len(detections))
+	for _, p := range pairs {
+		if matchedTrack[p.track] || matchedDet[p.det] {
+			continue
+		}
+		matchedTrack[p.track] = true
+		matchedDet[p.det] = true
+		t.tracks[p.track].add(now, &detections[p.det], t.opts.History)
+	}
+
+	// Age the unmatched tracks and drop the ones missing for too long.
+	kept := t.tracks[:0]
+	for i, tr := range t.tracks {
+		if !matchedTrack[i] {
+			tr.Missed++
+			if tr.Missed > t.opts.MaxMissed {
+				if tr.Hits >= t.opts.MinHits {
+					lost = append(lost, tr.clone())
+				}
+				continue
+			}
+		}
+		kept = append(kept, tr)
+	}
+	t.tracks = kept
+
+	for j := range detections {
+		if !matchedDet[j] {
+			tr := &Track{ID: t.nextID, FirstSeen: now}
+			t.nextID++
+			tr.add(now, &detections[j], t.opts.History)
+			t.tracks = append(t.tracks, tr)
+		}
+	}
+	return t.Tracks(), lost
+}
+
+// Tracks returns the confirmed tracks, as copies.
+func (t *Tracker) Tracks() []Track {
+	var out []Track
+	for _, tr := range t.tracks {
+		if tr.Hits >= t.opts.MinHits {
+			out = append(out, tr.clone())
+		}
+	}
+	return out
+}
+
+// Private details.
+
+// add adds a detection to the track at time now.
+func (t *Track) add(now time.Duration, b *blob.Blob, history int) {
+	if len(t.Trajectory) != 0 {
+		last := t.Trajectory[len(t.Trajectory)-1]
+		if dt := (now - last.Time).Seconds(); dt > 0 {
+			v := blob.Point{X: (b.Centroid.X - last.Pos.X) / dt, Y: (b.Centroid.Y - last.Pos.Y) / dt}
+			if t.Hits == 1 {
+				t.Velocity = v
+			} else {
+				// Smooth out the jitter of the centroid.
+				t.Velocity.X = (t.Velocity.X + v.X) / 2
+				t.Velocity.Y = (t.Velocity.Y + v.Y) / 2
+			}
+		}
+	}
+	t.Blob = *b
+	t.Trajectory = append(t.Trajectory, Sample{now, b.Centroid})
+	if history > 0 && len(t.Trajectory) > history {
+		t.Trajectory = append(t.Trajectory[:0], t.Trajectory[len(t.Trajectory)-history:]...)
+	}
+	t.LastSeen = now
+	t.Hits++
+	t.Missed = 0
+}
+
+func (t *Track) clone() Track {
+	c := *t
+	c.Trajectory = append([]Sample(nil), t.Trajectory...)
+	return c
+}
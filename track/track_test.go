@@ -0,0 +1,85 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package track
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/maruel/go-lepton/blob"
+	"periph.io/x/periph/devices/lepton"
+)
+
+func TestTracker(t *testing.T) {
+	tr := New(&Opts{MaxDistance: 5, MaxMissed: 3, MinHits: 2, History: 4})
+	m := &lepton.Metadata{}
+	step := func(dets ...blob.Point) ([]Track, []Track) {
+		m.SinceStartup += 100 * time.Millisecond
+		var b []blob.Blob
+		for _, d := range dets {
+			b = append(b, blob.Blob{Centroid: d})
+		}
+		return tr.Update(m, b)
+	}
+	// A single detection is not reported yet.
+	if a, _ := step(pt(10, 10)); len(a) != 0 {
+		t.Fatal(a)
+	}
+	// Two objects walking in opposite directions at 20 pixels per second.
+	a, _ := step(pt(12, 10), pt(60, 30))
+	if len(a) != 1 || a[0].ID != 1 || a[0].Velocity != (pt(20, 0)) {
+		t.Fatal(a)
+	}
+	step(pt(14, 10), pt(58, 30))
+	a, _ = step(pt(56, 30), pt(16, 10))
+	if len(a) != 2 || a[0].ID != 1 || a[1].ID != 2 {
+		t.Fatal(a)
+	}
+	if a[0].Pos() != (pt(16, 10)) || a[1].Pos() != (pt(56, 30)) {
+		t.Fatal(a)
+	}
+	if s := a[1].Speed(); math.Abs(s-20) > 0.001 {
+		t.Fatal(s)
+	}
+	if l := a[0].Lifetime(); l != 300*time.Millisecond {
+		t.Fatal(l)
+	}
+	if len(a[0].Trajectory) != 4 {
+		t.Fatal(a[0].Trajectory)
+	}
+
+	// The first object is occluded for 2 frames; it is picked back up where
+	// its velocity predicts it.
+	a, _ = step(pt(54, 30))
+	if len(a) != 2 || a[0].Missed != 1 {
+		t.Fatal(a)
+	}
+	step(pt(52, 30))
+	a, _ = step(pt(22, 10), pt(50, 30))
+	if len(a) != 2 || a[0].ID != 1 || a[0].Missed != 0 || a[0].Pos() != (pt(22, 10)) {
+		t.Fatal(a)
+	}
+
+	// The second object leaves.
+	for i := 0; i < 3; i++ {
+		if _, l := step(pt(24+2*float64(i), 10)); len(l) != 0 {
+			t.Fatal(l)
+		}
+	}
+	a, l := step(pt(30, 10))
+	if len(a) != 1 || a[0].ID != 1 || len(l) != 1 || l[0].ID != 2 {
+		t.Fatal(a, l)
+	}
+	// A new object gets a new ID.
+	step(pt(70, 50), pt(32, 10))
+	if a, _ = step(pt(70, 50), pt(34, 10)); len(a) != 2 || a[1].ID != 3 {
+		t.Fatal(a)
+	}
+}
+
+func pt(x, y float64) blob.Point {
+	return blob.Point{X: x, Y: y}
+}